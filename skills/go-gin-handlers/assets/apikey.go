@@ -0,0 +1,207 @@
+// internal/shared/auth/apikey/apikey.go
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role is the Claims.Role of every API-key caller.
+const Role = "partner"
+
+// Key is a partner's API key. Only the SHA-256 of the secret is stored; the
+// full key is shown once, when it's issued.
+//
+// Keys look like "bst_<prefix>_<secret>". The prefix is public and unique,
+// so lookups are a single indexed read, and it identifies the key in logs
+// and the admin UI without exposing the secret.
+type Key struct {
+	ID         string
+	Prefix     string
+	SecretHash string
+	Name       string
+	PartnerID  string // Becomes Claims.UserID
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func (k *Key) CheckUsable(now time.Time) error {
+	if k.RevokedAt != nil {
+		return ErrKeyRevoked
+	}
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return ErrKeyExpired
+	}
+	return nil
+}
+
+// Store persists keys. FindBy* return ErrKeyNotFound for unknown keys.
+type Store interface {
+	Create(ctx context.Context, key *Key) error
+	FindByID(ctx context.Context, id string) (*Key, error)
+	FindByPrefix(ctx context.Context, prefix string) (*Key, error)
+	// List returns a partner's keys, or all keys when partnerID is empty,
+	// newest first.
+	List(ctx context.Context, partnerID string) ([]*Key, error)
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+	Revoke(ctx context.Context, id string, at time.Time) error
+	// Rotate creates next and moves oldID's expiry to oldExpiresBy, unless
+	// it already expires sooner, in one transaction: if either write fails,
+	// neither happens.
+	Rotate(ctx context.Context, oldID string, next *Key, oldExpiresBy time.Time) error
+}
+
+var (
+	ErrKeyNotFound = notFoundError("api key not found")
+	ErrKeyRevoked  = conflictError("api key is revoked")
+	ErrKeyExpired  = conflictError("api key has expired")
+)
+
+type IssueInput struct {
+	Name      string
+	PartnerID string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// Manager issues, rotates and revokes keys for the admin endpoints.
+type Manager struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewManager(store Store) *Manager {
+	return &Manager{store: store, now: time.Now}
+}
+
+// Issue creates a key and returns it with the plaintext key, which can't
+// be recovered later.
+func (m *Manager) Issue(ctx context.Context, input IssueInput) (*Key, string, error) {
+	key, plaintext, err := m.newKey(input)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := m.store.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, plaintext, nil
+}
+
+// Rotate issues a replacement with the same partner, name and scopes, and
+// lets the old key keep working for overlap so the partner can deploy the
+// new one without downtime.
+func (m *Manager) Rotate(ctx context.Context, id string, overlap time.Duration, expiresAt *time.Time) (*Key, string, error) {
+	old, err := m.store.FindByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := old.CheckUsable(m.now()); err != nil {
+		return nil, "", err
+	}
+
+	next, plaintext, err := m.newKey(IssueInput{
+		Name:      old.Name,
+		PartnerID: old.PartnerID,
+		Scopes:    old.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	// One store call, so a failure can't leave an active key behind whose
+	// plaintext was never returned.
+	if err := m.store.Rotate(ctx, old.ID, next, m.now().Add(overlap)); err != nil {
+		return nil, "", err
+	}
+	return next, plaintext, nil
+}
+
+// Revoke disables a key immediately.
+func (m *Manager) Revoke(ctx context.Context, id string) error {
+	if _, err := m.store.FindByID(ctx, id); err != nil {
+		return err
+	}
+	return m.store.Revoke(ctx, id, m.now())
+}
+
+func (m *Manager) List(ctx context.Context, partnerID string) ([]*Key, error) {
+	return m.store.List(ctx, partnerID)
+}
+
+// newKey builds a key for input without storing it.
+func (m *Manager) newKey(input IssueInput) (*Key, string, error) {
+	now := m.now()
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(now) {
+		return nil, "", validationError("expires_at must be in the future")
+	}
+
+	prefix, secret, plaintext, err := generate()
+	if err != nil {
+		return nil, "", err
+	}
+	return &Key{
+		ID:         uuid.NewString(),
+		Prefix:     prefix,
+		SecretHash: hashSecret(secret),
+		Name:       input.Name,
+		PartnerID:  input.PartnerID,
+		Scopes:     input.Scopes,
+		ExpiresAt:  input.ExpiresAt,
+		CreatedAt:  now,
+	}, plaintext, nil
+}
+
+const keyPrefix = "bst"
+
+// generate returns a 48-bit hex prefix and a 256-bit secret.
+func generate() (prefix, secret, plaintext string, err error) {
+	b := make([]byte, 6+32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	prefix = hex.EncodeToString(b[:6])
+	secret = base64.RawURLEncoding.EncodeToString(b[6:])
+	return prefix, secret, keyPrefix + "_" + prefix + "_" + secret, nil
+}
+
+func parse(plaintext string) (prefix, secret string, ok bool) {
+	parts := strings.SplitN(plaintext, "_", 3) // The secret may contain "_"
+	if len(parts) != 3 || parts[0] != keyPrefix || len(parts[1]) != 12 || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// hashSecret uses SHA-256: the secret has full entropy, so a slow password
+// hash would only add latency to every request.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Errors implement the apperror marker interfaces.
+type notFoundError string
+
+func (e notFoundError) Error() string { return string(e) }
+func (notFoundError) NotFound()       {}
+
+type conflictError string
+
+func (e conflictError) Error() string { return string(e) }
+func (conflictError) Conflict()       {}
+
+type validationError string
+
+func (e validationError) Error() string { return string(e) }
+func (validationError) Validation()     {}
@@ -0,0 +1,143 @@
+// internal/shared/auth/apikey/http.go
+package apikey
+
+import (
+	"net/http"
+	"time"
+
+	"api/booking/internal/shared/middleware"
+	"api/booking/internal/shared/server"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler lets admins manage partner keys. Mount it on a group that
+// already runs AuthRequired.
+type AdminHandler struct {
+	manager *Manager
+}
+
+func NewAdminHandler(manager *Manager) *AdminHandler {
+	return &AdminHandler{manager: manager}
+}
+
+func (h *AdminHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	keys := rg.Group("/admin/api-keys", middleware.RequireRole("admin"))
+	{
+		keys.POST("", h.Create)
+		keys.GET("", h.List)
+		keys.POST("/:id/rotate", h.Rotate)
+		keys.DELETE("/:id", h.Revoke)
+	}
+}
+
+type KeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	PartnerID  string     `json:"partner_id"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IssuedKeyResponse is the only response that carries the plaintext key.
+type IssuedKeyResponse struct {
+	KeyResponse
+	Key string `json:"key"`
+}
+
+type CreateKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	PartnerID string     `json:"partner_id" binding:"required,uuid"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,min=1,max=64"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (h *AdminHandler) Create(c *gin.Context) {
+	var req CreateKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		server.FailWithDetails(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body", server.ValidationDetails(c, err))
+		return
+	}
+
+	key, plaintext, err := h.manager.Issue(c.Request.Context(), IssueInput{
+		Name:      req.Name,
+		PartnerID: req.PartnerID,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		server.HandleDomainError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	server.OK(c, http.StatusCreated, IssuedKeyResponse{KeyResponse: toResponse(key), Key: plaintext})
+}
+
+func (h *AdminHandler) List(c *gin.Context) {
+	keys, err := h.manager.List(c.Request.Context(), c.Query("partner_id"))
+	if err != nil {
+		server.HandleDomainError(c, err)
+		return
+	}
+
+	out := make([]KeyResponse, len(keys))
+	for i, k := range keys {
+		out[i] = toResponse(k)
+	}
+	server.OK(c, http.StatusOK, out)
+}
+
+type RotateKeyRequest struct {
+	// How long the old key keeps working; up to 30 days, default 24h.
+	OverlapSeconds int        `json:"overlap_seconds" binding:"omitempty,min=0,max=2592000"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+func (h *AdminHandler) Rotate(c *gin.Context) {
+	var req RotateKeyRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			server.FailWithDetails(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body", server.ValidationDetails(c, err))
+			return
+		}
+	}
+	overlap := 24 * time.Hour
+	if req.OverlapSeconds > 0 {
+		overlap = time.Duration(req.OverlapSeconds) * time.Second
+	}
+
+	key, plaintext, err := h.manager.Rotate(c.Request.Context(), c.Param("id"), overlap, req.ExpiresAt)
+	if err != nil {
+		server.HandleDomainError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	server.OK(c, http.StatusCreated, IssuedKeyResponse{KeyResponse: toResponse(key), Key: plaintext})
+}
+
+func (h *AdminHandler) Revoke(c *gin.Context) {
+	if err := h.manager.Revoke(c.Request.Context(), c.Param("id")); err != nil {
+		server.HandleDomainError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func toResponse(k *Key) KeyResponse {
+	return KeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		PartnerID:  k.PartnerID,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
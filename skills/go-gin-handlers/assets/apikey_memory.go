@@ -0,0 +1,109 @@
+// internal/shared/auth/apikey/memory.go
+package apikey
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+type InMemoryStore struct {
+	mu   sync.RWMutex
+	keys map[string]*Key // By ID
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{keys: make(map[string]*Key)}
+}
+
+func (s *InMemoryStore) Create(ctx context.Context, key *Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := *key
+	s.keys[key.ID] = &k
+	return nil
+}
+
+func (s *InMemoryStore) FindByID(ctx context.Context, id string) (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	k, ok := s.keys[id]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	c := *k
+	return &c, nil
+}
+
+func (s *InMemoryStore) FindByPrefix(ctx context.Context, prefix string) (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, k := range s.keys {
+		if k.Prefix == prefix {
+			c := *k
+			return &c, nil
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (s *InMemoryStore) List(ctx context.Context, partnerID string) ([]*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var keys []*Key
+	for _, k := range s.keys {
+		if partnerID == "" || k.PartnerID == partnerID {
+			c := *k
+			keys = append(keys, &c)
+		}
+	}
+	slices.SortFunc(keys, func(a, b *Key) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return keys, nil
+}
+
+func (s *InMemoryStore) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	return s.update(id, func(k *Key) { k.LastUsedAt = &at })
+}
+
+func (s *InMemoryStore) Revoke(ctx context.Context, id string, at time.Time) error {
+	return s.update(id, func(k *Key) {
+		if k.RevokedAt == nil {
+			k.RevokedAt = &at
+		}
+	})
+}
+
+func (s *InMemoryStore) ExpireBy(ctx context.Context, id string, at time.Time) error {
+	return s.update(id, func(k *Key) { expireBy(k, at) })
+}
+
+func (s *InMemoryStore) Rotate(ctx context.Context, oldID string, next *Key, oldExpiresBy time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.keys[oldID]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	expireBy(old, oldExpiresBy)
+	k := *next
+	s.keys[next.ID] = &k
+	return nil
+}
+
+func expireBy(k *Key, at time.Time) {
+	if k.ExpiresAt == nil || at.Before(*k.ExpiresAt) {
+		k.ExpiresAt = &at
+	}
+}
+
+func (s *InMemoryStore) update(id string, fn func(*Key)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	fn(k)
+	return nil
+}
@@ -0,0 +1,2 @@
+-- migrations/000012_create_api_keys.down.sql
+DROP TABLE IF EXISTS api_keys;
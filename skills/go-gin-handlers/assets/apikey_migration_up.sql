@@ -0,0 +1,17 @@
+-- migrations/000012_create_api_keys.up.sql
+CREATE TABLE api_keys (
+    id           UUID NOT NULL,
+    prefix       CHAR(12) NOT NULL,
+    secret_hash  CHAR(64) NOT NULL,
+    name         VARCHAR(100) NOT NULL,
+    partner_id   UUID NOT NULL,
+    scopes       TEXT[] NOT NULL DEFAULT '{}',
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_api_keys PRIMARY KEY (id),
+    CONSTRAINT uq_api_keys_prefix UNIQUE (prefix)
+);
+
+CREATE INDEX idx_api_keys_partner_id ON api_keys(partner_id);
@@ -0,0 +1,131 @@
+// internal/shared/auth/apikey/postgres.go
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"api/booking/internal/shared/auth/apikey/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresStore struct {
+	pool *pgxpool.Pool
+	q    *db.Queries
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool, q: db.New(pool)}
+}
+
+func (s *PostgresStore) Create(ctx context.Context, key *Key) error {
+	return createKey(ctx, s.q, key)
+}
+
+func createKey(ctx context.Context, q *db.Queries, key *Key) error {
+	err := q.CreateAPIKey(ctx, db.CreateAPIKeyParams{
+		ID:         key.ID,
+		Prefix:     key.Prefix,
+		SecretHash: key.SecretHash,
+		Name:       key.Name,
+		PartnerID:  key.PartnerID,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		CreatedAt:  key.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) FindByID(ctx context.Context, id string) (*Key, error) {
+	row, err := s.q.GetAPIKeyByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return toKey(row), nil
+}
+
+func (s *PostgresStore) FindByPrefix(ctx context.Context, prefix string) (*Key, error) {
+	row, err := s.q.GetAPIKeyByPrefix(ctx, prefix)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return toKey(row), nil
+}
+
+func (s *PostgresStore) List(ctx context.Context, partnerID string) ([]*Key, error) {
+	var partner *string
+	if partnerID != "" {
+		partner = &partnerID
+	}
+	rows, err := s.q.ListAPIKeys(ctx, partner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	keys := make([]*Key, len(rows))
+	for i, row := range rows {
+		keys[i] = toKey(row)
+	}
+	return keys, nil
+}
+
+func (s *PostgresStore) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	if err := s.q.TouchAPIKey(ctx, db.TouchAPIKeyParams{ID: id, LastUsedAt: &at}); err != nil {
+		return fmt.Errorf("failed to touch api key: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) Revoke(ctx context.Context, id string, at time.Time) error {
+	if err := s.q.RevokeAPIKey(ctx, db.RevokeAPIKeyParams{ID: id, RevokedAt: &at}); err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) Rotate(ctx context.Context, oldID string, next *Key, oldExpiresBy time.Time) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	q := s.q.WithTx(tx)
+
+	n, err := q.ExpireAPIKeyBy(ctx, db.ExpireAPIKeyByParams{ID: oldID, ExpiresAt: &oldExpiresBy})
+	if err != nil {
+		return fmt.Errorf("failed to set api key expiry: %w", err)
+	}
+	if n == 0 {
+		return ErrKeyNotFound
+	}
+	if err := createKey(ctx, q, next); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func toKey(row db.ApiKey) *Key {
+	return &Key{
+		ID:         row.ID,
+		Prefix:     row.Prefix,
+		SecretHash: row.SecretHash,
+		Name:       row.Name,
+		PartnerID:  row.PartnerID,
+		Scopes:     row.Scopes,
+		ExpiresAt:  row.ExpiresAt,
+		LastUsedAt: row.LastUsedAt,
+		RevokedAt:  row.RevokedAt,
+		CreatedAt:  row.CreatedAt,
+	}
+}
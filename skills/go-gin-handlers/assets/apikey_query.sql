@@ -0,0 +1,27 @@
+-- internal/shared/auth/apikey/query.sql
+
+-- name: CreateAPIKey :exec
+INSERT INTO api_keys (id, prefix, secret_hash, name, partner_id, scopes, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetAPIKeyByID :one
+SELECT * FROM api_keys WHERE id = $1;
+
+-- name: GetAPIKeyByPrefix :one
+SELECT * FROM api_keys WHERE prefix = $1;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_keys
+WHERE sqlc.narg('partner_id')::uuid IS NULL OR partner_id = sqlc.narg('partner_id')
+ORDER BY created_at DESC;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys SET last_used_at = $2 WHERE id = $1;
+
+-- name: RevokeAPIKey :exec
+UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL;
+
+-- Only ever shortens the lifetime: rotating a key that expires sooner than
+-- the overlap keeps its original expiry. LEAST ignores a NULL expires_at.
+-- name: ExpireAPIKeyBy :execrows
+UPDATE api_keys SET expires_at = LEAST(expires_at, $2) WHERE id = $1;
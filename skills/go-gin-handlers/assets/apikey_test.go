@@ -0,0 +1,227 @@
+// internal/shared/auth/apikey/apikey_test.go
+package apikey_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"api/booking/internal/shared/auth"
+	"api/booking/internal/shared/auth/apikey"
+	"api/booking/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+const partnerID = "5b0e2f0c-3c1e-4b43-9d0e-6f8b3c2a1d10"
+
+func issue(t *testing.T, m *apikey.Manager) (*apikey.Key, string) {
+	t.Helper()
+	key, plaintext, err := m.Issue(context.Background(), apikey.IssueInput{
+		Name:      "Clínica Las Condes",
+		PartnerID: partnerID,
+		Scopes:    []string{"bookings:read"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, plaintext
+}
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+	store := apikey.NewInMemoryStore()
+	manager := apikey.NewManager(store)
+	validator := apikey.NewValidator(store)
+	key, plaintext := issue(t, manager)
+
+	claims, err := validator.Validate(ctx, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != partnerID || claims.Role != apikey.Role || !claims.HasScope("bookings:read") {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	stored, _ := store.FindByID(ctx, key.ID)
+	if stored.LastUsedAt == nil {
+		t.Fatal("last_used_at not recorded")
+	}
+	firstUse := *stored.LastUsedAt
+	if _, err := validator.Validate(ctx, plaintext); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ = store.FindByID(ctx, key.ID)
+	if !stored.LastUsedAt.Equal(firstUse) {
+		t.Error("last_used_at should be written at most once a minute")
+	}
+
+	for name, token := range map[string]string{
+		"wrong secret":   plaintext[:len(plaintext)-4] + "AAAA",
+		"unknown prefix": "bst_000000000000_" + plaintext[len("bst_000000000000_"):],
+		"malformed":      "not-a-key",
+		"JWT":            "eyJhbGciOiJFUzI1NiJ9.e30.sig",
+	} {
+		if _, err := validator.Validate(ctx, token); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
+
+func TestRevokedAndExpired(t *testing.T) {
+	ctx := context.Background()
+	store := apikey.NewInMemoryStore()
+	manager := apikey.NewManager(store)
+	validator := apikey.NewValidator(store)
+
+	revoked, revokedKey := issue(t, manager)
+	if err := manager.Revoke(ctx, revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := validator.Validate(ctx, revokedKey); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("revoked key: err = %v", err)
+	}
+
+	expired, expiredKey := issue(t, manager)
+	if err := store.ExpireBy(ctx, expired.ID, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := validator.Validate(ctx, expiredKey); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("expired key: err = %v", err)
+	}
+
+	if _, _, err := manager.Rotate(ctx, revoked.ID, time.Hour, nil); !errors.Is(err, apikey.ErrKeyRevoked) {
+		t.Errorf("rotating a revoked key: err = %v", err)
+	}
+	if err := manager.Revoke(ctx, "missing"); !errors.Is(err, apikey.ErrKeyNotFound) {
+		t.Errorf("revoking unknown key: err = %v", err)
+	}
+}
+
+func TestRotateWithOverlap(t *testing.T) {
+	ctx := context.Background()
+	store := apikey.NewInMemoryStore()
+	manager := apikey.NewManager(store)
+	validator := apikey.NewValidator(store)
+
+	old, oldKey := issue(t, manager)
+	next, nextKey, err := manager.Rotate(ctx, old.ID, time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.PartnerID != old.PartnerID || next.Name != old.Name || next.Prefix == old.Prefix {
+		t.Fatalf("replacement = %+v", next)
+	}
+	for name, token := range map[string]string{"old": oldKey, "new": nextKey} {
+		if _, err := validator.Validate(ctx, token); err != nil {
+			t.Errorf("%s key during overlap: %v", name, err)
+		}
+	}
+
+	// Rotating again with no overlap cuts the previous key off at once.
+	_, newestKey, err := manager.Rotate(ctx, next.ID, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := validator.Validate(ctx, nextKey); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("key rotated without overlap: err = %v", err)
+	}
+	if _, err := validator.Validate(ctx, newestKey); err != nil {
+		t.Errorf("newest key: %v", err)
+	}
+}
+
+// failingRotate fails the rotation's store write, as a lost connection or a
+// concurrent delete of the old key would.
+type failingRotate struct {
+	*apikey.InMemoryStore
+}
+
+func (failingRotate) Rotate(context.Context, string, *apikey.Key, time.Time) error {
+	return errors.New("connection reset")
+}
+
+func TestRotateFailureLeavesNoNewKey(t *testing.T) {
+	ctx := context.Background()
+	store := apikey.NewInMemoryStore()
+	old, oldKey := issue(t, apikey.NewManager(store))
+
+	if _, _, err := apikey.NewManager(failingRotate{store}).Rotate(ctx, old.ID, 0, nil); err == nil {
+		t.Fatal("Rotate() succeeded with a failing store")
+	}
+	keys, err := store.List(ctx, partnerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0].ID != old.ID || keys[0].ExpiresAt != nil {
+		t.Errorf("keys after a failed rotation = %+v, want only the untouched old key", keys)
+	}
+	if _, err := apikey.NewValidator(store).Validate(ctx, oldKey); err != nil {
+		t.Errorf("old key after a failed rotation: %v", err)
+	}
+
+	// The store itself refuses to rotate a key that has gone away.
+	if err := store.Rotate(ctx, "missing", &apikey.Key{ID: "next", PartnerID: partnerID}, time.Now()); !errors.Is(err, apikey.ErrKeyNotFound) {
+		t.Errorf("Rotate(missing) error = %v, want ErrKeyNotFound", err)
+	}
+	if _, err := store.FindByID(ctx, "next"); !errors.Is(err, apikey.ErrKeyNotFound) {
+		t.Error("Rotate(missing) stored the new key")
+	}
+}
+
+type staticJWT map[string]*auth.Claims
+
+func (v staticJWT) Validate(_ context.Context, token string) (*auth.Claims, error) {
+	if c, ok := v[token]; ok {
+		return c, nil
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+func TestAuthRequiredWithAPIKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := apikey.NewInMemoryStore()
+	_, plaintext := issue(t, apikey.NewManager(store))
+	jwts := staticJWT{"user-token": {UserID: "u-1", Role: "owner"}}
+
+	r := gin.New()
+	r.GET("/me", middleware.AuthRequiredWithAPIKeys(jwts, apikey.NewValidator(store)), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("user_role"))
+	})
+
+	tests := []struct {
+		name     string
+		bearer   string
+		apiKey   string
+		wantCode int
+		wantRole string
+	}{
+		{"bearer JWT", "user-token", "", http.StatusOK, "owner"},
+		{"API key", "", plaintext, http.StatusOK, apikey.Role},
+		{"both", "user-token", plaintext, http.StatusUnauthorized, ""},
+		{"neither", "", "", http.StatusUnauthorized, ""},
+		{"bad API key", "", "bst_000000000000_nope", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantRole != "" && rec.Body.String() != tt.wantRole {
+				t.Errorf("role = %q, want %q", rec.Body.String(), tt.wantRole)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// internal/shared/auth/apikey/validator.go
+package apikey
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"api/booking/internal/shared/auth"
+)
+
+// Validator implements auth.TokenValidator for API keys, so partners go
+// through the same AuthRequired and authz policies as users.
+type Validator struct {
+	store Store
+	// touchEvery limits last_used_at writes to one per key per interval
+	// instead of one per request.
+	touchEvery time.Duration
+	now        func() time.Time
+}
+
+func NewValidator(store Store) *Validator {
+	return &Validator{store: store, touchEvery: time.Minute, now: time.Now}
+}
+
+var _ auth.TokenValidator = (*Validator)(nil)
+
+func (v *Validator) Validate(ctx context.Context, token string) (*auth.Claims, error) {
+	prefix, secret, ok := parse(token)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed api key", auth.ErrInvalidToken)
+	}
+
+	key, err := v.store.FindByPrefix(ctx, prefix)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: unknown api key %s", auth.ErrInvalidToken, prefix)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(key.SecretHash)) != 1 {
+		return nil, fmt.Errorf("%w: wrong secret for api key %s", auth.ErrInvalidToken, prefix)
+	}
+
+	now := v.now()
+	if err := key.CheckUsable(now); err != nil {
+		return nil, fmt.Errorf("%w: api key %s: %v", auth.ErrInvalidToken, prefix, err)
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= v.touchEvery {
+		if err := v.store.TouchLastUsed(ctx, key.ID, now); err != nil {
+			slog.WarnContext(ctx, "failed to record api key use", "error", err, "key_prefix", prefix)
+		}
+	}
+
+	return &auth.Claims{UserID: key.PartnerID, Role: Role, Scopes: key.Scopes}, nil
+}
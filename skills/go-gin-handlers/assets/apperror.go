@@ -0,0 +1,189 @@
+// internal/shared/apperror/apperror.go
+package apperror
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+)
+
+// Code is the transport-independent error category. Its value is the
+// "code" field of the JSON envelope, so existing clients keep working.
+type Code string
+
+const (
+	CodeValidation       Code = "VALIDATION_ERROR"    // Malformed request — 400
+	CodeUnauthorized     Code = "UNAUTHORIZED"        // 401
+	CodeForbidden        Code = "FORBIDDEN"           // 403
+	CodeNotFound         Code = "NOT_FOUND"           // 404
+	CodeConflict         Code = "CONFLICT"            // 409
+	CodeDomainValidation Code = "DOMAIN_VALIDATION"   // Well-formed but breaks a business rule — 422
+	CodeRateLimited      Code = "RATE_LIMITED"        // 429
+	CodeInternal         Code = "INTERNAL_ERROR"      // 500
+	CodeUnavailable      Code = "SERVICE_UNAVAILABLE" // 503
+	CodeTimeout          Code = "TIMEOUT"             // 504
+)
+
+var httpStatus = map[Code]int{
+	CodeValidation:       http.StatusBadRequest,
+	CodeUnauthorized:     http.StatusUnauthorized,
+	CodeForbidden:        http.StatusForbidden,
+	CodeNotFound:         http.StatusNotFound,
+	CodeConflict:         http.StatusConflict,
+	CodeDomainValidation: http.StatusUnprocessableEntity,
+	CodeRateLimited:      http.StatusTooManyRequests,
+	CodeInternal:         http.StatusInternalServerError,
+	CodeUnavailable:      http.StatusServiceUnavailable,
+	CodeTimeout:          http.StatusGatewayTimeout,
+}
+
+func (c Code) HTTPStatus() int {
+	if s, ok := httpStatus[c]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// FieldViolation points at the request field that caused the error.
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// Error is the single error type understood by HTTP and gRPC adapters.
+// Reason is a stable, machine-readable cause (e.g. "UPLOAD_EXPIRED") that
+// clients can switch on; Message is for humans.
+type Error struct {
+	Code     Code
+	Reason   string
+	Message  string
+	Fields   []FieldViolation
+	Metadata map[string]string
+	cause    error
+}
+
+// New declares a catalog entry, usually as a package-level sentinel:
+//
+//	var ErrBookingNotFound = apperror.New(apperror.CodeNotFound, "BOOKING_NOT_FOUND", "booking not found")
+func New(code Code, reason, message string) *Error {
+	return &Error{Code: code, Reason: reason, Message: message}
+}
+
+// Wrap attaches a code to an underlying error. The cause is kept for logs
+// and errors.Is/As but never sent to clients.
+func Wrap(err error, code Code, reason, message string) *Error {
+	return &Error{Code: code, Reason: reason, Message: message, cause: err}
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil && e.cause.Error() != e.Message {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.cause }
+
+// Is matches on Code and Reason, so a sentinel still matches after a copy
+// made by WithField or a round trip through gRPC.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.Code == t.Code && e.Reason == t.Reason
+}
+
+// WithField returns a copy with one more field violation; sentinels stay
+// untouched.
+func (e *Error) WithField(field, description string) *Error {
+	c := e.clone()
+	c.Fields = append(c.Fields, FieldViolation{Field: field, Description: description})
+	return c
+}
+
+func (e *Error) WithMetadata(key, value string) *Error {
+	c := e.clone()
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]string)
+	}
+	c.Metadata[key] = value
+	return c
+}
+
+// WithCause returns a copy wrapping err.
+func (e *Error) WithCause(err error) *Error {
+	c := e.clone()
+	c.cause = err
+	return c
+}
+
+func (e *Error) HTTPStatus() int { return e.Code.HTTPStatus() }
+
+// Details is the "details" member of the JSON envelope.
+type Details struct {
+	Reason   string            `json:"reason,omitempty"`
+	Fields   []FieldViolation  `json:"fields,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Details returns nil when there is nothing beyond code and message.
+func (e *Error) Details() *Details {
+	if e.Reason == "" && len(e.Fields) == 0 && len(e.Metadata) == 0 {
+		return nil
+	}
+	return &Details{Reason: e.Reason, Fields: e.Fields, Metadata: e.Metadata}
+}
+
+func (e *Error) clone() *Error {
+	c := *e
+	c.Fields = append([]FieldViolation(nil), e.Fields...)
+	c.Metadata = maps.Clone(e.Metadata)
+	return &c
+}
+
+// Marker interfaces for domain errors that don't import this package, e.g.
+// `type notFoundError string` with a NotFound() method.
+type (
+	NotFoundError     interface{ NotFound() }
+	ConflictError     interface{ Conflict() }
+	UnauthorizedError interface{ Unauthorized() }
+	ForbiddenError    interface{ Forbidden() }
+	ValidationError   interface{ Validation() }
+)
+
+// From classifies any error. Unknown errors become CodeInternal with a
+// generic message; the original is kept as the cause for logging.
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var (
+		appErr       *Error
+		notFound     NotFoundError
+		conflict     ConflictError
+		unauthorized UnauthorizedError
+		forbidden    ForbiddenError
+		validation   ValidationError
+	)
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.As(err, &notFound):
+		return Wrap(err, CodeNotFound, "", err.Error())
+	case errors.As(err, &conflict):
+		return Wrap(err, CodeConflict, "", err.Error())
+	case errors.As(err, &unauthorized):
+		return Wrap(err, CodeUnauthorized, "", err.Error())
+	case errors.As(err, &forbidden):
+		return Wrap(err, CodeForbidden, "", err.Error())
+	case errors.As(err, &validation):
+		return Wrap(err, CodeDomainValidation, "", err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(err, CodeTimeout, "", "The request timed out")
+	default:
+		return Wrap(err, CodeInternal, "", "An unexpected error occurred")
+	}
+}
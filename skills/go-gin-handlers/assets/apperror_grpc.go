@@ -0,0 +1,142 @@
+// internal/shared/apperror/grpc.go
+package apperror
+
+import (
+	"maps"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain identifies our services in errdetails.ErrorInfo.
+const ErrorDomain = "bastet"
+
+var grpcCodes = map[Code]codes.Code{
+	CodeValidation:       codes.InvalidArgument,
+	CodeUnauthorized:     codes.Unauthenticated,
+	CodeForbidden:        codes.PermissionDenied,
+	CodeNotFound:         codes.NotFound,
+	CodeConflict:         codes.AlreadyExists,
+	CodeDomainValidation: codes.FailedPrecondition,
+	CodeRateLimited:      codes.ResourceExhausted,
+	CodeInternal:         codes.Internal,
+	CodeUnavailable:      codes.Unavailable,
+	CodeTimeout:          codes.DeadlineExceeded,
+}
+
+func (c Code) GRPCCode() codes.Code {
+	if gc, ok := grpcCodes[c]; ok {
+		return gc
+	}
+	return codes.Internal
+}
+
+// CodeFromGRPC is used when a status carries no ErrorInfo, e.g. errors
+// raised by gRPC itself or by services outside this repo.
+func CodeFromGRPC(gc codes.Code) Code {
+	switch gc {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return CodeValidation
+	case codes.Unauthenticated:
+		return CodeUnauthorized
+	case codes.PermissionDenied:
+		return CodeForbidden
+	case codes.NotFound:
+		return CodeNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return CodeConflict
+	case codes.FailedPrecondition:
+		return CodeDomainValidation
+	case codes.ResourceExhausted:
+		return CodeRateLimited
+	case codes.Unavailable:
+		return CodeUnavailable
+	case codes.DeadlineExceeded:
+		return CodeTimeout
+	}
+	return CodeInternal
+}
+
+// GRPCStatus lets status.FromError and status.Code recognize *Error
+// directly. Code travels in ErrorInfo.Metadata so the client gets back the
+// exact Code, not just the coarser gRPC code.
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(e.Code.GRPCCode(), e.Message)
+
+	meta := maps.Clone(e.Metadata)
+	if meta == nil {
+		meta = make(map[string]string, 1)
+	}
+	meta["code"] = string(e.Code)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   e.Reason,
+		Domain:   ErrorDomain,
+		Metadata: meta,
+	}}
+	if len(e.Fields) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, f := range e.Fields {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.Field,
+				Description: f.Description,
+			})
+		}
+		details = append(details, br)
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st // Details are an extra; code and message still go through
+	}
+	return withDetails
+}
+
+// ToGRPC converts any error returned by an application service into a
+// status error for a gRPC handler to return.
+func ToGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+	return From(err).GRPCStatus().Err()
+}
+
+// FromGRPC decodes a status error from a client call back into *Error, so
+// errors.Is against catalog sentinels works across the service boundary.
+// Errors that are not gRPC statuses go through From.
+func FromGRPC(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return From(err)
+	}
+
+	e := &Error{Code: CodeFromGRPC(st.Code()), Message: st.Message(), cause: err}
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() != ErrorDomain {
+				continue
+			}
+			e.Reason = d.GetReason()
+			for k, v := range d.GetMetadata() {
+				if k == "code" {
+					e.Code = Code(v)
+					continue
+				}
+				if e.Metadata == nil {
+					e.Metadata = make(map[string]string)
+				}
+				e.Metadata[k] = v
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				e.Fields = append(e.Fields, FieldViolation{Field: v.GetField(), Description: v.GetDescription()})
+			}
+		}
+	}
+	return e
+}
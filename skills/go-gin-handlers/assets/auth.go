@@ -0,0 +1,58 @@
+// internal/shared/auth/auth.go
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// Claims is what transports know about the caller once a token checks out.
+type Claims struct {
+	UserID string
+	Role   string
+	Scopes []string
+}
+
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+type claimsKey struct{}
+
+// WithClaims attaches the authenticated caller to ctx. Transports call it
+// once the token checks out; authz policies and services read it back.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFrom returns the caller attached by WithClaims.
+func ClaimsFrom(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+type tokenKey struct{}
+
+// WithToken keeps the caller's raw bearer token in ctx so outgoing calls
+// made on their behalf can forward it.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// TokenFrom returns the token stored by WithToken.
+func TokenFrom(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenKey{}).(string)
+	return token, ok && token != ""
+}
+
+// ErrInvalidToken is returned for any token that must not be accepted.
+// Validators wrap it with the specific reason for logs; clients only ever
+// see a generic 401.
+var ErrInvalidToken = errors.New("invalid token")
+
+// TokenValidator is a port — implementations live in infrastructure
+// (jwtauth for JWTs).
+type TokenValidator interface {
+	Validate(ctx context.Context, token string) (*Claims, error)
+}
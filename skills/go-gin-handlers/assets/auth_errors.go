@@ -0,0 +1,32 @@
+// internal/auth/domain/errors.go
+package domain
+
+// The marker methods let apperror.From map these without this package
+// importing it.
+var (
+	ErrUserNotFound = notFoundError("user not found")
+	ErrEmailTaken   = conflictError("email already registered")
+	// ErrInvalidCredentials covers both an unknown email and a wrong
+	// password, so login doesn't reveal which accounts exist.
+	ErrInvalidCredentials = unauthorizedError("invalid email or password")
+)
+
+type notFoundError string
+
+func (e notFoundError) Error() string { return string(e) }
+func (notFoundError) NotFound()       {}
+
+type conflictError string
+
+func (e conflictError) Error() string { return string(e) }
+func (conflictError) Conflict()       {}
+
+type unauthorizedError string
+
+func (e unauthorizedError) Error() string { return string(e) }
+func (unauthorizedError) Unauthorized()   {}
+
+type validationError string
+
+func (e validationError) Error() string { return string(e) }
+func (validationError) Validation()     {}
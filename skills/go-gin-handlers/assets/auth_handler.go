@@ -0,0 +1,132 @@
+// internal/auth/infrastructure/handler/http.go
+package handler
+
+import (
+	"net/http"
+
+	"api/auth/internal/auth/application"
+	"api/auth/internal/shared/server"
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	service      *application.AuthService
+	authRequired gin.HandlerFunc
+}
+
+// NewAuthHandler takes the AuthRequired middleware for the routes that act
+// on the caller's own sessions; the rest are public.
+func NewAuthHandler(service *application.AuthService, authRequired gin.HandlerFunc) *AuthHandler {
+	return &AuthHandler{service: service, authRequired: authRequired}
+}
+
+// RegisterRoutes mounts all routes for this domain.
+func (h *AuthHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	auth := rg.Group("/auth")
+	{
+		auth.POST("/register", h.Register)
+		auth.POST("/login", h.Login)
+		auth.POST("/refresh", h.Refresh)
+		auth.POST("/logout", h.Logout)
+		auth.POST("/logout-all", h.authRequired, h.LogoutAll)
+	}
+}
+
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required,min=10,max=128"`
+}
+
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		server.FailWithDetails(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body", server.ValidationDetails(c, err))
+		return
+	}
+
+	tokens, err := h.service.Register(c.Request.Context(), application.RegisterInput{
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		server.HandleDomainError(c, err)
+		return
+	}
+
+	noStore(c)
+	server.OK(c, http.StatusCreated, tokens)
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		server.FailWithDetails(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body", server.ValidationDetails(c, err))
+		return
+	}
+
+	tokens, err := h.service.Login(c.Request.Context(), application.LoginInput{
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		server.HandleDomainError(c, err)
+		return
+	}
+
+	noStore(c)
+	server.OK(c, http.StatusOK, tokens)
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		server.FailWithDetails(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body", server.ValidationDetails(c, err))
+		return
+	}
+
+	tokens, err := h.service.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		server.HandleDomainError(c, err)
+		return
+	}
+
+	noStore(c)
+	server.OK(c, http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		server.FailWithDetails(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body", server.ValidationDetails(c, err))
+		return
+	}
+
+	if err := h.service.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		server.HandleDomainError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	if err := h.service.LogoutAll(c.Request.Context(), c.GetString("user_id")); err != nil {
+		server.HandleDomainError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// noStore keeps tokens out of shared and browser caches (RFC 6749 §5.1).
+func noStore(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+}
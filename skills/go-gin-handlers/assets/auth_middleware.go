@@ -2,6 +2,7 @@
 package middleware
 
 import (
+	"api/booking/internal/shared/auth"
 	"api/booking/internal/shared/server"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -9,16 +10,36 @@ import (
 )
 
 func AuthRequired(tokenValidator TokenValidator) gin.HandlerFunc {
+	return AuthRequiredWithAPIKeys(tokenValidator, nil)
+}
+
+// AuthRequiredWithAPIKeys also accepts partner API keys in X-API-Key,
+// checked by apiKeys (apikey.Validator). A request must use one scheme:
+// sending both is rejected rather than guessing which one counts.
+func AuthRequiredWithAPIKeys(tokenValidator, apiKeys TokenValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		header := c.GetHeader("Authorization")
-		if !strings.HasPrefix(header, "Bearer ") {
+		var apiKey string
+		if apiKeys != nil {
+			apiKey = c.GetHeader("X-API-Key")
+		}
+
+		var (
+			token  string
+			claims *auth.Claims
+			err    error
+		)
+		switch {
+		case apiKey != "" && header == "":
+			claims, err = apiKeys.Validate(c.Request.Context(), apiKey)
+		case apiKey == "" && strings.HasPrefix(header, "Bearer "):
+			token = strings.TrimPrefix(header, "Bearer ")
+			claims, err = tokenValidator.Validate(c.Request.Context(), token)
+		default:
 			server.Fail(c, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid authorization header")
 			c.Abort()
 			return
 		}
-
-		token := strings.TrimPrefix(header, "Bearer ")
-		claims, err := tokenValidator.Validate(c.Request.Context(), token)
 		if err != nil {
 			server.Fail(c, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid token")
 			c.Abort()
@@ -27,11 +48,18 @@ func AuthRequired(tokenValidator TokenValidator) gin.HandlerFunc {
 
 		c.Set("user_id", claims.UserID)
 		c.Set("user_role", claims.Role)
+		c.Set("user_scopes", claims.Scopes)
+		ctx := auth.WithClaims(c.Request.Context(), claims)
+		if token != "" {
+			// Only JWTs are forwarded downstream; other services can't
+			// validate API keys, so those calls use service credentials.
+			ctx = auth.WithToken(ctx, token)
+		}
+		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
 }
 
-// TokenValidator is a port — implementation is in infrastructure
-type TokenValidator interface {
-	Validate(ctx context.Context, token string) (*Claims, error)
-}
+// TokenValidator is the auth port; jwtauth.Validator and apikey.Validator
+// implement it.
+type TokenValidator = auth.TokenValidator
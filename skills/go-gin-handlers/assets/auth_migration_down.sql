@@ -0,0 +1,2 @@
+-- migrations/000002_create_refresh_tokens.down.sql
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,86 @@
+// internal/auth/infrastructure/password/argon2id_test.go
+package password_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"api/auth/internal/auth/infrastructure/password"
+)
+
+var testParams = password.Params{Memory: 1024, Time: 1, Threads: 1, SaltLen: 16, KeyLen: 32}
+
+func TestArgon2id_HashAndVerify(t *testing.T) {
+	h := password.NewArgon2id(testParams)
+	hash, err := h.Hash("correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("Hash() = %q, want PHC format with the parameters", hash)
+	}
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"correct horse battery", true},
+		{"correct horse batterY", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if ok, err := h.Verify(tt.password, hash); err != nil || ok != tt.want {
+			t.Errorf("Verify(%q) = %v, %v; want %v", tt.password, ok, err, tt.want)
+		}
+	}
+
+	again, err := h.Hash("correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == hash {
+		t.Error("Hash() of the same password twice is identical; salts must differ")
+	}
+}
+
+func TestArgon2id_VerifyUsesTheHashParameters(t *testing.T) {
+	old, err := password.NewArgon2id(testParams).Hash("correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raised := testParams
+	raised.Memory, raised.Time = 2048, 2
+	if ok, err := password.NewArgon2id(raised).Verify("correct horse battery", old); err != nil || !ok {
+		t.Errorf("Verify() after raising parameters = %v, %v; want true", ok, err)
+	}
+}
+
+func TestArgon2id_MalformedHash(t *testing.T) {
+	h := password.NewArgon2id(testParams)
+	valid, err := h.Hash("correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(valid, "$")
+
+	tests := []struct {
+		name, encoded string
+	}{
+		{"empty", ""},
+		{"bcrypt", "$2a$10$abcdefghijklmnopqrstuu"},
+		{"argon2i", strings.Replace(valid, "$argon2id$", "$argon2i$", 1)},
+		{"other version", strings.Replace(valid, "$v=19$", "$v=16$", 1)},
+		{"bad params", strings.Replace(valid, parts[3], "m=x,t=1,p=1", 1)},
+		{"bad salt", strings.Replace(valid, parts[4], "!!!", 1)},
+		{"empty key", strings.TrimSuffix(valid, parts[5])},
+		{"extra field", valid + "$x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := h.Verify("correct horse battery", tt.encoded); !errors.Is(err, password.ErrMalformedHash) {
+				t.Errorf("Verify() error = %v, want ErrMalformedHash", err)
+			}
+		})
+	}
+}
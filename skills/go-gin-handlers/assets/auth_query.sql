@@ -0,0 +1,26 @@
+-- internal/auth/infrastructure/repository/refresh_token_query.sql
+
+-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (id, family_id, user_id, token_hash, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetRefreshTokenByHash :one
+SELECT * FROM refresh_tokens WHERE token_hash = $1;
+
+-- Succeeds for exactly one caller per token: concurrent refreshes with the
+-- same token find rotated_at already set and affect no rows.
+-- name: MarkRefreshTokenRotated :execrows
+UPDATE refresh_tokens
+SET rotated_at = $2
+WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens SET revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- Rows are kept past expiry for a while so reuse of an old token is still
+-- detected; purge them afterwards.
+-- name: DeleteExpiredRefreshTokens :execrows
+DELETE FROM refresh_tokens WHERE expires_at < $1;
@@ -0,0 +1,71 @@
+// internal/auth/infrastructure/repository/refresh_token_memory.go
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"api/auth/internal/auth/domain"
+)
+
+type InMemoryRefreshTokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[string]*domain.RefreshToken // By hash
+}
+
+func NewInMemoryRefreshTokenRepository() *InMemoryRefreshTokenRepository {
+	return &InMemoryRefreshTokenRepository{tokens: make(map[string]*domain.RefreshToken)}
+}
+
+func (r *InMemoryRefreshTokenRepository) Save(ctx context.Context, token *domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := *token
+	r.tokens[token.TokenHash] = &t
+	return nil
+}
+
+func (r *InMemoryRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+	c := *t
+	return &c, nil
+}
+
+func (r *InMemoryRefreshTokenRepository) Rotate(ctx context.Context, current, next *domain.RefreshToken, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[current.TokenHash]
+	if !ok || t.RotatedAt != nil || t.RevokedAt != nil {
+		return domain.ErrRefreshTokenReused
+	}
+	t.RotatedAt = &now
+	n := *next
+	r.tokens[next.TokenHash] = &n
+	return nil
+}
+
+func (r *InMemoryRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, now time.Time) error {
+	r.revokeWhere(func(t *domain.RefreshToken) bool { return t.FamilyID == familyID }, now)
+	return nil
+}
+
+func (r *InMemoryRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID domain.UserID, now time.Time) error {
+	r.revokeWhere(func(t *domain.RefreshToken) bool { return t.UserID == userID }, now)
+	return nil
+}
+
+func (r *InMemoryRefreshTokenRepository) revokeWhere(match func(*domain.RefreshToken) bool, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if match(t) && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+}
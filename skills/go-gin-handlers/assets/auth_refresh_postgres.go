@@ -0,0 +1,108 @@
+// internal/auth/infrastructure/repository/refresh_token_postgres.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"api/auth/internal/auth/domain"
+	"api/auth/internal/auth/infrastructure/repository/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresRefreshTokenRepository struct {
+	pool *pgxpool.Pool
+	q    *db.Queries
+}
+
+func NewPostgresRefreshTokenRepository(pool *pgxpool.Pool) *PostgresRefreshTokenRepository {
+	return &PostgresRefreshTokenRepository{pool: pool, q: db.New(pool)}
+}
+
+func (r *PostgresRefreshTokenRepository) Save(ctx context.Context, token *domain.RefreshToken) error {
+	return saveRefreshToken(ctx, r.q, token)
+}
+
+func (r *PostgresRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	row, err := r.q.GetRefreshTokenByHash(ctx, tokenHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+	return &domain.RefreshToken{
+		ID:        row.ID,
+		FamilyID:  row.FamilyID,
+		UserID:    domain.UserID(row.UserID),
+		TokenHash: row.TokenHash,
+		ExpiresAt: row.ExpiresAt,
+		RotatedAt: row.RotatedAt,
+		RevokedAt: row.RevokedAt,
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
+
+func (r *PostgresRefreshTokenRepository) Rotate(ctx context.Context, current, next *domain.RefreshToken, now time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	q := r.q.WithTx(tx)
+
+	n, err := q.MarkRefreshTokenRotated(ctx, db.MarkRefreshTokenRotatedParams{ID: current.ID, RotatedAt: &now})
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if n == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+	if err := saveRefreshToken(ctx, q, next); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, now time.Time) error {
+	if err := r.q.RevokeRefreshTokenFamily(ctx, db.RevokeRefreshTokenFamilyParams{FamilyID: familyID, RevokedAt: &now}); err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID domain.UserID, now time.Time) error {
+	if err := r.q.RevokeUserRefreshTokens(ctx, db.RevokeUserRefreshTokensParams{UserID: userID.String(), RevokedAt: &now}); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired deletes tokens that expired before cutoff. Keep a margin
+// (e.g. cutoff = now - 30 days) so replays of recently expired tokens still
+// hit a row and are reported as expired rather than unknown.
+func (r *PostgresRefreshTokenRepository) PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	n, err := r.q.DeleteExpiredRefreshTokens(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge refresh tokens: %w", err)
+	}
+	return n, nil
+}
+
+func saveRefreshToken(ctx context.Context, q *db.Queries, token *domain.RefreshToken) error {
+	err := q.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+		ID:        token.ID,
+		FamilyID:  token.FamilyID,
+		UserID:    token.UserID.String(),
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return nil
+}
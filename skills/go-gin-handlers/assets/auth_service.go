@@ -0,0 +1,247 @@
+// internal/auth/application/auth_service.go
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"api/auth/internal/auth/domain"
+	"api/auth/internal/shared/auth"
+)
+
+// PasswordHasher is implemented by password.Argon2id.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+}
+
+// AccessTokenIssuer is implemented by jwtauth.Signer.
+type AccessTokenIssuer interface {
+	Sign(claims auth.Claims) (token string, expiresAt time.Time, err error)
+}
+
+type AuthService struct {
+	users      domain.UserRepository
+	tokens     domain.RefreshTokenRepository
+	hasher     PasswordHasher
+	issuer     AccessTokenIssuer
+	refreshTTL time.Duration
+	now        func() time.Time
+
+	// dummyHash is verified when the email is unknown, so a login takes as
+	// long whether or not the account exists.
+	dummyHash string
+}
+
+func NewAuthService(
+	users domain.UserRepository,
+	tokens domain.RefreshTokenRepository,
+	hasher PasswordHasher,
+	issuer AccessTokenIssuer,
+	refreshTTL time.Duration,
+) (*AuthService, error) {
+	dummy, err := hasher.Hash("not-a-real-password")
+	if err != nil {
+		return nil, err
+	}
+	return &AuthService{
+		users:      users,
+		tokens:     tokens,
+		hasher:     hasher,
+		issuer:     issuer,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+		dummyHash:  dummy,
+	}, nil
+}
+
+type RegisterInput struct {
+	Email    string
+	Password string
+}
+
+type LoginInput struct {
+	Email    string
+	Password string
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // Seconds until the access token expires
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Register creates an owner account and logs it in. A taken email is
+// reported by Save as ErrEmailTaken: checking first would race with a
+// concurrent registration.
+func (s *AuthService) Register(ctx context.Context, input RegisterInput) (*TokenPair, error) {
+	email, err := domain.NewEmail(input.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.ValidatePassword(input.Password); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.hasher.Hash(input.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user, err := domain.NewUser(email, hash, domain.RoleOwner, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.users.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return s.issue(ctx, user)
+}
+
+func (s *AuthService) Login(ctx context.Context, input LoginInput) (*TokenPair, error) {
+	user, err := s.findByEmail(ctx, input.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := s.dummyHash
+	if user != nil {
+		hash = user.PasswordHash
+	}
+	ok, err := s.hasher.Verify(input.Password, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify password: %w", err)
+	}
+	if !ok || user == nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	return s.issue(ctx, user)
+}
+
+// Refresh exchanges a refresh token for a new pair. Presenting a token that
+// was already exchanged revokes its whole family: the legitimate client and
+// whoever replayed the token both have to log in again.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	now := s.now()
+	current, err := s.tokens.FindByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := current.CheckUsable(now); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			s.revokeFamily(ctx, current, now)
+		}
+		return nil, err
+	}
+
+	user, err := s.users.FindByID(ctx, current.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	next := domain.NewRefreshToken(user.ID, current.FamilyID, hash, s.refreshTTL, now)
+	if err := s.tokens.Rotate(ctx, current, next, now); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			s.revokeFamily(ctx, current, now)
+		}
+		return nil, err
+	}
+
+	return s.pair(user, raw)
+}
+
+// Logout revokes the session the refresh token belongs to. Unknown or
+// already revoked tokens are not an error, so logout is safe to retry.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	current, err := s.tokens.FindByHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, domain.ErrRefreshTokenInvalid) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.tokens.RevokeFamily(ctx, current.FamilyID, s.now())
+}
+
+// LogoutAll revokes every session of the user, e.g. after a password change.
+// Access tokens already issued stay valid until they expire.
+func (s *AuthService) LogoutAll(ctx context.Context, userID string) error {
+	return s.tokens.RevokeAllForUser(ctx, domain.UserID(userID), s.now())
+}
+
+// issue starts a new token family for a fresh login.
+func (s *AuthService) issue(ctx context.Context, user *domain.User) (*TokenPair, error) {
+	raw, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokens.Save(ctx, domain.NewRefreshToken(user.ID, "", hash, s.refreshTTL, s.now())); err != nil {
+		return nil, err
+	}
+	return s.pair(user, raw)
+}
+
+func (s *AuthService) pair(user *domain.User, refreshToken string) (*TokenPair, error) {
+	access, exp, err := s.issuer.Sign(auth.Claims{UserID: user.ID.String(), Role: string(user.Role)})
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(exp.Sub(s.now()).Round(time.Second).Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// findByEmail returns nil, nil for unknown or malformed emails so Login can
+// spend the same time on them.
+func (s *AuthService) findByEmail(ctx context.Context, raw string) (*domain.User, error) {
+	email, err := domain.NewEmail(raw)
+	if err != nil {
+		return nil, nil
+	}
+	user, err := s.users.FindByEmail(ctx, email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (s *AuthService) revokeFamily(ctx context.Context, token *domain.RefreshToken, now time.Time) {
+	slog.WarnContext(ctx, "refresh token reuse detected, revoking session",
+		"user_id", token.UserID, "family_id", token.FamilyID)
+	if err := s.tokens.RevokeFamily(ctx, token.FamilyID, now); err != nil {
+		slog.ErrorContext(ctx, "failed to revoke token family", "error", err, "family_id", token.FamilyID)
+	}
+}
+
+// newRefreshToken returns an opaque 256-bit token and the SHA-256 we store.
+// A fast hash is enough: unlike passwords, the input has full entropy.
+func newRefreshToken() (raw, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	raw = base64.RawURLEncoding.EncodeToString(b)
+	return raw, hashToken(raw), nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
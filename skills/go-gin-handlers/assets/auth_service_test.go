@@ -0,0 +1,205 @@
+// internal/auth/application/auth_service_test.go
+package application_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"api/auth/internal/auth/application"
+	"api/auth/internal/auth/domain"
+	"api/auth/internal/auth/infrastructure/password"
+	"api/auth/internal/auth/infrastructure/repository"
+	"api/auth/internal/shared/auth"
+)
+
+// Cheap parameters keep the tests fast; the scheme is the real one.
+var testParams = password.Params{Memory: 1024, Time: 1, Threads: 1, SaltLen: 16, KeyLen: 32}
+
+type fakeIssuer struct{}
+
+func (fakeIssuer) Sign(claims auth.Claims) (string, time.Time, error) {
+	return "access-" + claims.UserID, time.Now().Add(15 * time.Minute), nil
+}
+
+func newService(t *testing.T, refreshTTL time.Duration) *application.AuthService {
+	t.Helper()
+	svc, err := application.NewAuthService(
+		repository.NewInMemoryUserRepository(),
+		repository.NewInMemoryRefreshTokenRepository(),
+		password.NewArgon2id(testParams),
+		fakeIssuer{},
+		refreshTTL,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func register(t *testing.T, svc *application.AuthService) *application.TokenPair {
+	t.Helper()
+	pair, err := svc.Register(context.Background(), application.RegisterInput{Email: "ana@example.com", Password: "correct horse battery"})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	return pair
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t, time.Hour)
+	pair := register(t, svc)
+	if pair.TokenType != "Bearer" || pair.AccessToken == "" || pair.RefreshToken == "" {
+		t.Fatalf("Register() = %+v, want a bearer token pair", pair)
+	}
+
+	if _, err := svc.Register(ctx, application.RegisterInput{Email: "ana@example.com", Password: "another password"}); !errors.Is(err, domain.ErrEmailTaken) {
+		t.Errorf("Register() twice: error = %v, want ErrEmailTaken", err)
+	}
+	if _, err := svc.Login(ctx, application.LoginInput{Email: "ana@example.com", Password: "correct horse battery"}); err != nil {
+		t.Errorf("Login() error = %v", err)
+	}
+
+	tests := []struct {
+		name, email, password string
+	}{
+		{"wrong password", "ana@example.com", "wrong horse battery"},
+		{"unknown email", "bob@example.com", "correct horse battery"},
+		{"malformed email", "not-an-email", "correct horse battery"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Login(ctx, application.LoginInput{Email: tt.email, Password: tt.password})
+			if !errors.Is(err, domain.ErrInvalidCredentials) {
+				t.Errorf("Login() error = %v, want ErrInvalidCredentials", err)
+			}
+		})
+	}
+}
+
+func TestRegister_ConcurrentSignUpsWithOneEmail(t *testing.T) {
+	svc := newService(t, time.Hour)
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created int
+	)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Register(context.Background(), application.RegisterInput{Email: "ana@example.com", Password: "correct horse battery"})
+			switch {
+			case err == nil:
+				mu.Lock()
+				created++
+				mu.Unlock()
+			case !errors.Is(err, domain.ErrEmailTaken):
+				t.Errorf("Register() error = %v, want ErrEmailTaken", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if created != 1 {
+		t.Errorf("%d accounts created, want 1", created)
+	}
+}
+
+func TestRefresh_RotatesTheToken(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t, time.Hour)
+	first := register(t, svc)
+
+	second, err := svc.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("Refresh() returned the presented refresh token")
+	}
+	if _, err := svc.Refresh(ctx, second.RefreshToken); err != nil {
+		t.Errorf("Refresh() with the rotated token: error = %v", err)
+	}
+	if _, err := svc.Refresh(ctx, "unknown"); !errors.Is(err, domain.ErrRefreshTokenInvalid) {
+		t.Errorf("Refresh() with an unknown token: error = %v, want ErrRefreshTokenInvalid", err)
+	}
+}
+
+func TestRefresh_ReuseRevokesTheFamily(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t, time.Hour)
+	stolen := register(t, svc)
+	other, err := svc.Login(ctx, application.LoginInput{Email: "ana@example.com", Password: "correct horse battery"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := svc.Refresh(ctx, stolen.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Refresh(ctx, stolen.RefreshToken); !errors.Is(err, domain.ErrRefreshTokenReused) {
+		t.Fatalf("replayed Refresh() error = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, err := svc.Refresh(ctx, current.RefreshToken); !errors.Is(err, domain.ErrRefreshTokenRevoked) {
+		t.Errorf("Refresh() after reuse: error = %v, want ErrRefreshTokenRevoked", err)
+	}
+	if _, err := svc.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Errorf("Refresh() of another session: error = %v", err)
+	}
+}
+
+func TestRefresh_ConcurrentExchangesOfOneToken(t *testing.T) {
+	svc := newService(t, time.Hour)
+	pair := register(t, svc)
+
+	errs := make(chan error, 8)
+	for range 8 {
+		go func() {
+			_, err := svc.Refresh(context.Background(), pair.RefreshToken)
+			errs <- err
+		}()
+	}
+	succeeded := 0
+	for range 8 {
+		if err := <-errs; err == nil {
+			succeeded++
+		}
+	}
+	if succeeded > 1 {
+		t.Errorf("%d refreshes succeeded, want at most 1", succeeded)
+	}
+}
+
+func TestRefresh_Expired(t *testing.T) {
+	svc := newService(t, -time.Second) // Tokens are born expired
+	pair := register(t, svc)
+	if _, err := svc.Refresh(context.Background(), pair.RefreshToken); !errors.Is(err, domain.ErrRefreshTokenExpired) {
+		t.Errorf("Refresh() error = %v, want ErrRefreshTokenExpired", err)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t, time.Hour)
+	first := register(t, svc)
+	second, err := svc.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.Logout(ctx, second.RefreshToken); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if _, err := svc.Refresh(ctx, second.RefreshToken); !errors.Is(err, domain.ErrRefreshTokenRevoked) {
+		t.Errorf("Refresh() after Logout: error = %v, want ErrRefreshTokenRevoked", err)
+	}
+	for _, token := range []string{second.RefreshToken, "unknown"} {
+		if err := svc.Logout(ctx, token); err != nil {
+			t.Errorf("Logout(%q) retry: error = %v", token, err)
+		}
+	}
+}
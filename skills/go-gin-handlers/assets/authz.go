@@ -0,0 +1,164 @@
+// internal/shared/authz/authz.go
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"api/booking/internal/shared/auth"
+)
+
+// Request is what a policy decides on: the authenticated caller and, for
+// routes like /bookings/:id, the ID of the resource being accessed.
+type Request struct {
+	Claims     *auth.Claims
+	ResourceID string
+}
+
+// Policy returns nil to allow the request. Denials are forbidden errors
+// (403); any other error, like a not found from an ownership loader, is
+// passed through as is.
+type Policy func(ctx context.Context, r Request) error
+
+// ErrUnauthenticated is returned by Check when there is no caller at all.
+var ErrUnauthenticated = unauthorizedError("authentication required")
+
+// Check evaluates policy for r. It is the single entry point for transports,
+// so policies can assume r.Claims is set.
+func Check(ctx context.Context, policy Policy, r Request) error {
+	if r.Claims == nil {
+		return ErrUnauthenticated
+	}
+	return policy(ctx, r)
+}
+
+// Authenticated allows any caller with valid claims.
+func Authenticated(context.Context, Request) error { return nil }
+
+// Role allows callers whose role is any of roles.
+func Role(roles ...string) Policy {
+	return func(_ context.Context, r Request) error {
+		if slices.Contains(roles, r.Claims.Role) {
+			return nil
+		}
+		return forbiddenError(fmt.Sprintf("requires role %s", strings.Join(roles, " or ")))
+	}
+}
+
+// Scope allows callers whose token carries every one of scopes.
+func Scope(scopes ...string) Policy {
+	return func(_ context.Context, r Request) error {
+		for _, s := range scopes {
+			if !r.Claims.HasScope(s) {
+				return forbiddenError(fmt.Sprintf("missing scope %s", s))
+			}
+		}
+		return nil
+	}
+}
+
+// Owner loads the resource and allows the caller if they are one of the
+// parties returned for it (e.g. a booking's owner and caregiver). Load
+// errors are returned unchanged, so an unknown ID is still a 404. An allowed
+// resource is kept for Resource, so the handler doesn't load it again.
+func Owner[T any](load func(ctx context.Context, id string) (T, error), parties func(T) []string) Policy {
+	return func(ctx context.Context, r Request) error {
+		if r.ResourceID == "" {
+			return errors.New("authz: owner policy used on a route without a resource ID")
+		}
+		resource, err := load(ctx, r.ResourceID)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(parties(resource), r.Claims.UserID) {
+			return forbiddenError("you don't have access to this resource")
+		}
+		if slot, ok := ctx.Value(resourceKey{}).(*resourceSlot); ok {
+			slot.value = resource
+		}
+		return nil
+	}
+}
+
+type resourceKey struct{}
+
+type resourceSlot struct{ value any }
+
+// WithResourceSlot returns a context in which Owner keeps the resource it
+// allowed. Transports call it before Check and pass the context on.
+func WithResourceSlot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resourceKey{}, &resourceSlot{})
+}
+
+// Resource returns the resource Owner loaded for this request. ok is false
+// when no Owner policy ran — AnyOf lets admins through before it — so the
+// caller must load the resource itself.
+func Resource[T any](ctx context.Context) (T, bool) {
+	var resource T
+	slot, ok := ctx.Value(resourceKey{}).(*resourceSlot)
+	if !ok {
+		return resource, false
+	}
+	resource, ok = slot.value.(T)
+	return resource, ok
+}
+
+// AllOf allows the request only if every policy does. It stops at the first
+// error.
+func AllOf(policies ...Policy) Policy {
+	return func(ctx context.Context, r Request) error {
+		for _, p := range policies {
+			if err := p(ctx, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// AnyOf allows the request if one policy does. Put cheap policies first:
+// AnyOf(Role("admin"), Owner(...)) never loads the resource for admins.
+// Errors other than denials stop evaluation; otherwise the first denial is
+// returned.
+func AnyOf(policies ...Policy) Policy {
+	return func(ctx context.Context, r Request) error {
+		var denied error
+		for _, p := range policies {
+			err := p(ctx, r)
+			if err == nil {
+				return nil
+			}
+			if !IsDenied(err) {
+				return err
+			}
+			if denied == nil {
+				denied = err
+			}
+		}
+		if denied == nil {
+			denied = forbiddenError("access denied")
+		}
+		return denied
+	}
+}
+
+// IsDenied reports whether err is a policy denial.
+func IsDenied(err error) bool {
+	var f interface{ Forbidden() }
+	return errors.As(err, &f)
+}
+
+// Errors implement the apperror marker interfaces, so HandleDomainError and
+// the gRPC adapter map them to 403 / 401.
+type forbiddenError string
+
+func (e forbiddenError) Error() string { return string(e) }
+func (forbiddenError) Forbidden()      {}
+
+type unauthorizedError string
+
+func (e unauthorizedError) Error() string { return string(e) }
+func (unauthorizedError) Unauthorized()   {}
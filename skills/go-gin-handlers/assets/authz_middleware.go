@@ -0,0 +1,37 @@
+// internal/shared/middleware/authz.go
+package middleware
+
+import (
+	"api/booking/internal/shared/auth"
+	"api/booking/internal/shared/authz"
+	"api/booking/internal/shared/server"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole allows callers with any of roles. Mount it after AuthRequired.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return Authorize(authz.Role(roles...))
+}
+
+// RequireScope allows callers whose token carries every one of scopes.
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return Authorize(authz.Scope(scopes...))
+}
+
+// Authorize enforces policy on a route. The resource ID is the :id path
+// parameter, if the route has one. Denials render as 403 through
+// HandleDomainError; a missing caller as 401. A resource loaded by an Owner
+// policy is available to the handler through authz.Resource.
+func Authorize(policy authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := authz.WithResourceSlot(c.Request.Context())
+		claims, _ := auth.ClaimsFrom(ctx)
+		if err := authz.Check(ctx, policy, authz.Request{Claims: claims, ResourceID: c.Param("id")}); err != nil {
+			server.HandleDomainError(c, err)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
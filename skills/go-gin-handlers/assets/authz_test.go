@@ -0,0 +1,152 @@
+// internal/shared/authz/authz_test.go
+package authz_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api/booking/internal/shared/apperror"
+	"api/booking/internal/shared/auth"
+	"api/booking/internal/shared/authz"
+	"api/booking/internal/shared/authz/authztest"
+	"api/booking/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type booking struct {
+	OwnerID     string
+	CaregiverID string
+}
+
+type notFoundError string
+
+func (e notFoundError) Error() string { return string(e) }
+func (notFoundError) NotFound()       {}
+
+var bookings = map[string]booking{
+	"b-1": {OwnerID: "owner-1", CaregiverID: "carer-1"},
+}
+
+func loadBooking(_ context.Context, id string) (booking, error) {
+	b, ok := bookings[id]
+	if !ok {
+		return booking{}, notFoundError("booking not found")
+	}
+	return b, nil
+}
+
+func bookingParties(b booking) []string { return []string{b.OwnerID, b.CaregiverID} }
+
+var (
+	owner     = &auth.Claims{UserID: "owner-1", Role: "owner"}
+	caregiver = &auth.Claims{UserID: "carer-1", Role: "caregiver"}
+	stranger  = &auth.Claims{UserID: "owner-2", Role: "owner"}
+	admin     = &auth.Claims{UserID: "admin-1", Role: "admin"}
+	partner   = &auth.Claims{UserID: "app-1", Role: "partner", Scopes: []string{"bookings:read", "bookings:write"}}
+)
+
+func TestRole(t *testing.T) {
+	authztest.Run(t, authz.Role("caregiver", "admin"), []authztest.Case{
+		{Name: "caregiver", Claims: caregiver, Want: authztest.Allow},
+		{Name: "admin", Claims: admin, Want: authztest.Allow},
+		{Name: "owner", Claims: owner, Want: apperror.CodeForbidden},
+		{Name: "anonymous", Want: apperror.CodeUnauthorized},
+	})
+}
+
+func TestScope(t *testing.T) {
+	authztest.Run(t, authz.Scope("bookings:read", "bookings:write"), []authztest.Case{
+		{Name: "all scopes", Claims: partner, Want: authztest.Allow},
+		{Name: "one scope missing", Claims: &auth.Claims{UserID: "app-2", Scopes: []string{"bookings:read"}}, Want: apperror.CodeForbidden},
+		{Name: "no scopes", Claims: owner, Want: apperror.CodeForbidden},
+	})
+}
+
+func TestOwner(t *testing.T) {
+	policy := authz.AnyOf(authz.Role("admin"), authz.Owner(loadBooking, bookingParties))
+
+	authztest.Run(t, policy, []authztest.Case{
+		{Name: "owner", Claims: owner, ResourceID: "b-1", Want: authztest.Allow},
+		{Name: "caregiver", Claims: caregiver, ResourceID: "b-1", Want: authztest.Allow},
+		{Name: "admin", Claims: admin, ResourceID: "b-1", Want: authztest.Allow},
+		{Name: "admin skips loading", Claims: admin, ResourceID: "missing", Want: authztest.Allow},
+		{Name: "stranger", Claims: stranger, ResourceID: "b-1", Want: apperror.CodeForbidden},
+		{Name: "unknown booking", Claims: owner, ResourceID: "missing", Want: apperror.CodeNotFound},
+		{Name: "no resource ID", Claims: owner, Want: apperror.CodeInternal},
+	})
+}
+
+func TestAllOf(t *testing.T) {
+	authztest.Run(t, authz.AllOf(authz.Role("partner"), authz.Scope("bookings:write")), []authztest.Case{
+		{Name: "role and scope", Claims: partner, Want: authztest.Allow},
+		{Name: "scope without role", Claims: &auth.Claims{UserID: "app-2", Role: "owner", Scopes: []string{"bookings:write"}}, Want: apperror.CodeForbidden},
+		{Name: "role without scope", Claims: &auth.Claims{UserID: "app-3", Role: "partner"}, Want: apperror.CodeForbidden},
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("RequireRole", func(t *testing.T) {
+		authztest.RunMiddleware(t, middleware.RequireRole("caregiver"), []authztest.Case{
+			{Name: "caregiver", Claims: caregiver, Want: authztest.Allow},
+			{Name: "owner", Claims: owner, Want: apperror.CodeForbidden},
+			{Name: "anonymous", Want: apperror.CodeUnauthorized},
+		})
+	})
+
+	t.Run("RequireScope", func(t *testing.T) {
+		authztest.RunMiddleware(t, middleware.RequireScope("bookings:write"), []authztest.Case{
+			{Name: "partner", Claims: partner, Want: authztest.Allow},
+			{Name: "owner", Claims: owner, Want: apperror.CodeForbidden},
+		})
+	})
+
+	t.Run("Authorize", func(t *testing.T) {
+		authztest.RunMiddleware(t, middleware.Authorize(authz.Owner(loadBooking, bookingParties)), []authztest.Case{
+			{Name: "owner", Claims: owner, ResourceID: "b-1", Want: authztest.Allow},
+			{Name: "stranger", Claims: stranger, ResourceID: "b-1", Want: apperror.CodeForbidden},
+			{Name: "unknown booking", Claims: owner, ResourceID: "missing", Want: apperror.CodeNotFound},
+		})
+	})
+}
+
+func TestAuthorize_HandsTheLoadedResourceOn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	loads := 0
+	load := func(ctx context.Context, id string) (booking, error) {
+		loads++
+		return loadBooking(ctx, id)
+	}
+	policy := authz.AnyOf(authz.Role("admin"), authz.Owner(load, bookingParties))
+
+	tests := []struct {
+		name      string
+		claims    *auth.Claims
+		wantLoads int
+		wantFound bool
+	}{
+		{"owner", owner, 1, true},
+		{"admin", admin, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loads = 0
+			var got booking
+			found := false
+			r := gin.New()
+			r.GET("/bookings/:id", middleware.Authorize(policy), func(c *gin.Context) {
+				got, found = authz.Resource[booking](c.Request.Context())
+			})
+			req := httptest.NewRequest(http.MethodGet, "/bookings/b-1", nil)
+			r.ServeHTTP(httptest.NewRecorder(), req.WithContext(auth.WithClaims(req.Context(), tt.claims)))
+
+			if loads != tt.wantLoads || found != tt.wantFound {
+				t.Fatalf("loads = %d, found = %v; want %d, %v", loads, found, tt.wantLoads, tt.wantFound)
+			}
+			if found && got != bookings["b-1"] {
+				t.Errorf("Resource() = %+v, want b-1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// internal/shared/authz/authztest/authztest.go
+package authztest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api/booking/internal/shared/apperror"
+	"api/booking/internal/shared/auth"
+	"api/booking/internal/shared/authz"
+	"github.com/gin-gonic/gin"
+)
+
+// Allow is the expected outcome of a case that must pass.
+const Allow apperror.Code = ""
+
+// Case is one row of a policy table. Leave Claims nil for an
+// unauthenticated caller.
+type Case struct {
+	Name       string
+	Claims     *auth.Claims
+	ResourceID string
+	Want       apperror.Code // Allow, CodeForbidden, CodeUnauthorized, CodeNotFound...
+}
+
+// Run checks policy against every case, one subtest per row.
+func Run(t *testing.T, policy authz.Policy, cases []Case) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := authz.Check(context.Background(), policy, authz.Request{Claims: tc.Claims, ResourceID: tc.ResourceID})
+			var got apperror.Code
+			if err != nil {
+				got = apperror.From(err).Code
+			}
+			if got != tc.Want {
+				t.Errorf("got %q (%v), want %q", got, err, tc.Want)
+			}
+		})
+	}
+}
+
+// RunMiddleware checks a Gin authorization middleware end to end: each case
+// is sent to GET /resources/:id (or GET /resources without a ResourceID)
+// and the response status is compared with the HTTP status of Want.
+func RunMiddleware(t *testing.T, mw gin.HandlerFunc, cases []Case) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := gin.New()
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			r.GET("/resources", mw, ok)
+			r.GET("/resources/:id", mw, ok)
+
+			path := "/resources"
+			if tc.ResourceID != "" {
+				path += "/" + tc.ResourceID
+			}
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tc.Claims != nil {
+				req = req.WithContext(auth.WithClaims(req.Context(), tc.Claims))
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			want := http.StatusOK
+			if tc.Want != Allow {
+				want = tc.Want.HTTPStatus()
+			}
+			if rec.Code != want {
+				t.Errorf("status = %d, want %d; body %s", rec.Code, want, rec.Body)
+			}
+		})
+	}
+}
@@ -2,35 +2,48 @@
 package server
 
 import (
-	"errors"
+	"log/slog"
+
+	"api/booking/internal/shared/apperror"
 	"github.com/gin-gonic/gin"
-	"net/http"
 )
 
-// Domain error sentinel types — defined in each domain's error.go
-type NotFoundError interface{ NotFound() }
-type ConflictError interface{ Conflict() }
-type ForbiddenError interface{ Forbidden() }
-type ValidationError interface{ Validation() }
+// Kept so existing domain packages can keep referring to server.NotFoundError.
+type (
+	NotFoundError   = apperror.NotFoundError
+	ConflictError   = apperror.ConflictError
+	ForbiddenError  = apperror.ForbiddenError
+	ValidationError = apperror.ValidationError
+)
 
+// HandleDomainError renders any error from the application layer. Domain
+// errors are either *apperror.Error catalog entries or implement the
+// apperror marker interfaces (NotFound(), Conflict(), Forbidden(), Validation()).
 func HandleDomainError(c *gin.Context, err error) {
-	var (
-		notFound   NotFoundError
-		conflict   ConflictError
-		forbidden  ForbiddenError
-		validation ValidationError
-	)
+	appErr := apperror.From(err)
+	if appErr.Code == apperror.CodeInternal {
+		slog.ErrorContext(c.Request.Context(), "unhandled error", "error", err, "path", c.FullPath())
+	}
+	FailWithError(c, appErr)
+}
+
+// FailWithError writes the envelope for an *apperror.Error: code, message
+// and, when present, reason, field violations and metadata as details.
+func FailWithError(c *gin.Context, err *apperror.Error) {
+	body := &Error{Code: string(err.Code), Message: err.Message}
+	if d := err.Details(); d != nil {
+		body.Details = (*domainDetails)(d)
+	}
+	writeError(c, err.HTTPStatus(), body)
+}
+
+// domainDetails renders apperror.Details: reason and metadata become
+// problem members and field violations become "errors".
+type domainDetails apperror.Details
 
-	switch {
-	case errors.As(err, &notFound):
-		Fail(c, http.StatusNotFound, "NOT_FOUND", err.Error())
-	case errors.As(err, &conflict):
-		Fail(c, http.StatusConflict, "CONFLICT", err.Error())
-	case errors.As(err, &forbidden):
-		Fail(c, http.StatusForbidden, "FORBIDDEN", err.Error())
-	case errors.As(err, &validation):
-		Fail(c, http.StatusUnprocessableEntity, "DOMAIN_VALIDATION", err.Error())
-	default:
-		Fail(c, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred")
+func (d *domainDetails) ProblemMembers(p *Problem) {
+	p.Reason, p.Metadata = d.Reason, d.Metadata
+	if len(d.Fields) > 0 {
+		p.Errors = d.Fields
 	}
 }
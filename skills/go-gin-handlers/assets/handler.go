@@ -3,6 +3,10 @@ package handler
 
 import (
 	"api/booking/internal/booking/application"
+	"api/booking/internal/booking/domain"
+	"api/booking/internal/shared/authz"
+	"api/booking/internal/shared/middleware"
+	"api/booking/internal/shared/pagination"
 	"api/booking/internal/shared/server"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -16,17 +20,51 @@ func NewBookingHandler(service *application.BookingService) *BookingHandler {
 	return &BookingHandler{service: service}
 }
 
-// RegisterRoutes mounts all routes for this domain.
+// RegisterRoutes mounts all routes for this domain. The group must already
+// run AuthRequired; policies here only decide who may do what.
 func (h *BookingHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	parties := middleware.Authorize(h.bookingParties())
+
 	bookings := rg.Group("/bookings")
 	{
-		bookings.POST("", h.Create)
-		bookings.GET("/:id", h.GetByID)
+		bookings.POST("", middleware.RequireRole("owner"), h.Create)
+		bookings.GET("/:id", parties, h.GetByID)
 		bookings.GET("", h.ListByOwner)
-		bookings.PATCH("/:id/cancel", h.Cancel)
+		bookings.PATCH("/:id/cancel", parties, h.Cancel)
 	}
 }
 
+// bookingParties lets the booking's owner and caregiver through; admins
+// skip the lookup.
+func (h *BookingHandler) bookingParties() authz.Policy {
+	return authz.AnyOf(
+		authz.Role("admin"),
+		authz.Owner(h.service.GetBooking, func(b *domain.Booking) []string {
+			return []string{b.OwnerID, b.CaregiverID}
+		}),
+	)
+}
+
+func (h *BookingHandler) GetByID(c *gin.Context) {
+	booking, err := h.booking(c)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	server.OK(c, http.StatusOK, booking)
+}
+
+// booking returns the booking bookingParties already loaded, so a request
+// reads it once. Admins pass without the lookup; load it for them here.
+func (h *BookingHandler) booking(c *gin.Context) (*domain.Booking, error) {
+	ctx := c.Request.Context()
+	if b, ok := authz.Resource[*domain.Booking](ctx); ok {
+		return b, nil
+	}
+	return h.service.GetBooking(ctx, c.Param("id"))
+}
+
 type CreateBookingRequest struct {
 	CaregiverID string   `json:"caregiver_id" binding:"required,uuid"`
 	ServiceType string   `json:"service_type" binding:"required,oneof=walk hosting visit specialized"`
@@ -38,7 +76,7 @@ type CreateBookingRequest struct {
 func (h *BookingHandler) Create(c *gin.Context) {
 	var req CreateBookingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		server.FailWithDetails(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body", err.Error())
+		server.FailWithDetails(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body", server.ValidationDetails(c, err))
 		return
 	}
 
@@ -60,3 +98,28 @@ func (h *BookingHandler) Create(c *gin.Context) {
 
 	server.OK(c, http.StatusCreated, output)
 }
+
+// ListByOwner returns the caller's bookings, newest first unless sorted:
+// GET /bookings?filter[status]=confirmed&sort=start_at&per_page=20&cursor=…
+// → data + meta{per_page, next_cursor, prev_cursor} + Link header. A
+// tampered cursor is a 400.
+func (h *BookingHandler) ListByOwner(c *gin.Context) {
+	q, err := server.ParseQuery(c, domain.BookingQuery)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+	page, err := server.ParseKeyset(c, pagination.DefaultLimits)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	result, err := h.service.ListByOwner(c.Request.Context(), c.GetString("user_id"), q, page)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	server.OKWithKeysetPage(c, page, result)
+}
@@ -0,0 +1,61 @@
+// internal/shared/idempotency/idempotency.go
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Record is one Idempotency-Key, scoped to the user that sent it. Status is
+// zero while the first request is still being handled.
+type Record struct {
+	ID          string // Changes whenever the key is claimed again
+	UserID      string
+	Key         string
+	Fingerprint string // SHA-256 of method, path and body
+
+	Status int
+	Header http.Header
+	Body   []byte
+
+	ExpiresAt   time.Time // The key can be reused with another body after this
+	LockedUntil time.Time // An unfinished claim is abandoned after this
+}
+
+func (r *Record) Completed() bool { return r.Status != 0 }
+
+// Claim asks for a key. Now is passed in so every store judges expiry by
+// the same (application) clock.
+type Claim struct {
+	UserID      string
+	Key         string
+	Fingerprint string
+	Now         time.Time
+	TTL         time.Duration
+	LockTimeout time.Duration
+}
+
+// Response is what Complete stores for replay.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+var ErrNotClaimed = errors.New("idempotency key not claimed")
+
+// Store persists keys and responses.
+type Store interface {
+	// Begin claims the key. If a live record already exists it is returned
+	// with claimed=false and nothing changes. Expired records and claims
+	// whose lock timed out are taken over.
+	Begin(ctx context.Context, claim Claim) (rec *Record, claimed bool, err error)
+	// Complete stores the response for the claim with this record ID.
+	// ErrNotClaimed means the claim was lost, e.g. taken over after its
+	// lock timed out.
+	Complete(ctx context.Context, id string, resp Response) error
+	// Release drops an unfinished claim so the client can retry.
+	Release(ctx context.Context, id string) error
+}
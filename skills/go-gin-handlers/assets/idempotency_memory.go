@@ -0,0 +1,84 @@
+// internal/shared/idempotency/memory.go
+package idempotency
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type scope struct{ userID, key string }
+
+// InMemoryStore implements Store for tests and single-instance development.
+type InMemoryStore struct {
+	mu      sync.Mutex
+	records map[scope]*Record
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{records: make(map[scope]*Record)}
+}
+
+func (s *InMemoryStore) Begin(_ context.Context, claim Claim) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := scope{claim.UserID, claim.Key}
+	if rec, ok := s.records[k]; ok && claim.Now.Before(rec.ExpiresAt) &&
+		(rec.Completed() || claim.Now.Before(rec.LockedUntil)) {
+		return copyRecord(rec), false, nil
+	}
+
+	rec := &Record{
+		ID:          uuid.NewString(),
+		UserID:      claim.UserID,
+		Key:         claim.Key,
+		Fingerprint: claim.Fingerprint,
+		ExpiresAt:   claim.Now.Add(claim.TTL),
+		LockedUntil: claim.Now.Add(claim.LockTimeout),
+	}
+	s.records[k] = rec
+	return copyRecord(rec), true, nil
+}
+
+func (s *InMemoryStore) Complete(_ context.Context, id string, resp Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := s.find(id)
+	if rec == nil || rec.Completed() {
+		return ErrNotClaimed
+	}
+	rec.Status = resp.Status
+	rec.Header = resp.Header.Clone()
+	rec.Body = slices.Clone(resp.Body)
+	return nil
+}
+
+func (s *InMemoryStore) Release(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec := s.find(id); rec != nil && !rec.Completed() {
+		delete(s.records, scope{rec.UserID, rec.Key})
+	}
+	return nil
+}
+
+func (s *InMemoryStore) find(id string) *Record {
+	for _, rec := range s.records {
+		if rec.ID == id {
+			return rec
+		}
+	}
+	return nil
+}
+
+func copyRecord(r *Record) *Record {
+	c := *r
+	c.Header = r.Header.Clone()
+	c.Body = slices.Clone(r.Body)
+	return &c
+}
@@ -0,0 +1,204 @@
+// internal/shared/middleware/idempotency.go
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"api/booking/internal/shared/apperror"
+	"api/booking/internal/shared/idempotency"
+	"api/booking/internal/shared/server"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed marks a response served from the store.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+var (
+	ErrIdempotencyKeyRequired = apperror.New(apperror.CodeValidation, "IDEMPOTENCY_KEY_REQUIRED", "Idempotency-Key header is required")
+	ErrIdempotencyKeyInvalid  = apperror.New(apperror.CodeValidation, "IDEMPOTENCY_KEY_INVALID", "Idempotency-Key must be 1 to 255 characters")
+	ErrIdempotencyKeyInFlight = apperror.New(apperror.CodeConflict, "IDEMPOTENCY_KEY_IN_FLIGHT", "A request with this Idempotency-Key is still being processed")
+	ErrIdempotencyKeyReused   = apperror.New(apperror.CodeDomainValidation, "IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used with a different request")
+	ErrRequestTooLarge        = apperror.New(apperror.CodeValidation, "REQUEST_TOO_LARGE", "Request body is too large")
+)
+
+type IdempotencyConfig struct {
+	TTL          time.Duration // How long responses are replayed; default 24h
+	LockTimeout  time.Duration // When an unfinished claim is considered abandoned; default 1m
+	MaxBodyBytes int64         // Largest request body fingerprinted; default 1 MiB
+	Required     bool          // Reject requests without the header
+}
+
+// Idempotency replays the stored response when a client retries a request
+// with the same Idempotency-Key. Keys are scoped to user_id, so it must run
+// after AuthRequired:
+//
+//	bookings.POST("", middleware.Idempotency(store, middleware.IdempotencyConfig{Required: true}), h.Create)
+//
+// A retry while the first request is running gets 409 with Retry-After; the
+// same key with a different method, path or body gets 422. 5xx responses
+// are not stored, so the client can retry them.
+func Idempotency(store idempotency.Store, cfg IdempotencyConfig) gin.HandlerFunc {
+	if cfg.TTL == 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.LockTimeout == 0 {
+		cfg.LockTimeout = time.Minute
+	}
+	if cfg.MaxBodyBytes == 0 {
+		cfg.MaxBodyBytes = 1 << 20
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if key == "" && !cfg.Required {
+			c.Next()
+			return
+		}
+		if key == "" {
+			abortWithError(c, ErrIdempotencyKeyRequired)
+			return
+		}
+		if len(key) > 255 {
+			abortWithError(c, ErrIdempotencyKeyInvalid)
+			return
+		}
+		userID := c.GetString("user_id")
+		if userID == "" {
+			server.Fail(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+			c.Abort()
+			return
+		}
+
+		fingerprint, ferr := fingerprintRequest(c, cfg.MaxBodyBytes)
+		if ferr != nil {
+			abortWithError(c, ferr)
+			return
+		}
+
+		ctx := c.Request.Context()
+		rec, claimed, err := store.Begin(ctx, idempotency.Claim{
+			UserID:      userID,
+			Key:         key,
+			Fingerprint: fingerprint,
+			Now:         time.Now(),
+			TTL:         cfg.TTL,
+			LockTimeout: cfg.LockTimeout,
+		})
+		if err != nil {
+			server.HandleDomainError(c, err)
+			c.Abort()
+			return
+		}
+
+		if !claimed {
+			switch {
+			case rec.Fingerprint != fingerprint:
+				abortWithError(c, ErrIdempotencyKeyReused)
+			case !rec.Completed():
+				c.Header("Retry-After", "1")
+				abortWithError(c, ErrIdempotencyKeyInFlight)
+			default:
+				replay(c, rec)
+			}
+			return
+		}
+
+		// The claim must not outlive a failed handler, including a panic
+		// recovered further up the chain. Store calls use a context that
+		// survives the client disconnecting.
+		storeCtx := context.WithoutCancel(ctx)
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := store.Release(storeCtx, rec.ID); err != nil {
+				slog.ErrorContext(ctx, "failed to release idempotency key", "error", err, "key", key)
+			}
+		}()
+
+		rw := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = rw
+		c.Next()
+
+		if rw.Status() >= http.StatusInternalServerError {
+			return
+		}
+		header := rw.Header().Clone()
+		header.Del("Set-Cookie")
+		header.Del("Date")
+		err = store.Complete(storeCtx, rec.ID, idempotency.Response{
+			Status: rw.Status(),
+			Header: header,
+			Body:   rw.body.Bytes(),
+		})
+		if err != nil {
+			// The response was already sent; a retry will run the handler
+			// again, so this is worth an alert.
+			slog.ErrorContext(ctx, "failed to store idempotent response", "error", err, "key", key)
+			return
+		}
+		completed = true
+	}
+}
+
+// fingerprintRequest hashes method, URI and body, and restores the body for
+// the handler.
+func fingerprintRequest(c *gin.Context, maxBytes int64) (string, *apperror.Error) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return "", ErrRequestTooLarge
+		}
+		return "", apperror.Wrap(err, apperror.CodeValidation, "REQUEST_BODY_UNREADABLE", "Request body could not be read")
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	h := sha256.New()
+	io.WriteString(h, c.Request.Method+"\n"+c.Request.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func replay(c *gin.Context, rec *idempotency.Record) {
+	for name, values := range rec.Header {
+		c.Writer.Header()[name] = values
+	}
+	c.Header(HeaderIdempotentReplayed, "true")
+	c.Status(rec.Status)
+	c.Writer.Write(rec.Body)
+	c.Abort()
+}
+
+func abortWithError(c *gin.Context, err *apperror.Error) {
+	server.FailWithError(c, err)
+	c.Abort()
+}
+
+// recordingWriter keeps a copy of the body while writing it through.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
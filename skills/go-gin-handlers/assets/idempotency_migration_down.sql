@@ -0,0 +1,2 @@
+-- migrations/000011_create_idempotency_keys.down.sql
+DROP TABLE IF EXISTS idempotency_keys;
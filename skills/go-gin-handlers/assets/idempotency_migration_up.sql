@@ -0,0 +1,18 @@
+-- migrations/000011_create_idempotency_keys.up.sql
+CREATE TABLE idempotency_keys (
+    id               UUID NOT NULL,
+    user_id          UUID NOT NULL,
+    idempotency_key  VARCHAR(255) NOT NULL,
+    fingerprint      CHAR(64) NOT NULL,
+    response_status  INTEGER,
+    response_headers JSONB,
+    response_body    BYTEA,
+    locked_until     TIMESTAMPTZ NOT NULL,
+    expires_at       TIMESTAMPTZ NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_idempotency_keys PRIMARY KEY (id),
+    CONSTRAINT uq_idempotency_keys_user_id_idempotency_key UNIQUE (user_id, idempotency_key),
+    CONSTRAINT ck_idempotency_keys_response_status CHECK (response_status BETWEEN 100 AND 599)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
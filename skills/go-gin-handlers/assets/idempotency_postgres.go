@@ -0,0 +1,123 @@
+// internal/shared/idempotency/postgres.go
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"api/booking/internal/shared/idempotency/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore implements Store. The unique (user_id, idempotency_key)
+// constraint arbitrates concurrent claims; no explicit locks are held while
+// the handler runs.
+type PostgresStore struct {
+	q *db.Queries
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{q: db.New(pool)}
+}
+
+func (s *PostgresStore) Begin(ctx context.Context, claim Claim) (*Record, bool, error) {
+	// A live record can be released between the failed claim and the read;
+	// the second attempt then claims it.
+	for attempt := 0; ; attempt++ {
+		row, err := s.q.ClaimIdempotencyKey(ctx, db.ClaimIdempotencyKeyParams{
+			ID:             uuid.NewString(),
+			UserID:         claim.UserID,
+			IdempotencyKey: claim.Key,
+			Fingerprint:    claim.Fingerprint,
+			LockedUntil:    claim.Now.Add(claim.LockTimeout),
+			ExpiresAt:      claim.Now.Add(claim.TTL),
+			Now:            claim.Now,
+		})
+		if err == nil {
+			rec, err := toRecord(row)
+			return rec, true, err
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+
+		row, err = s.q.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{UserID: claim.UserID, IdempotencyKey: claim.Key})
+		if errors.Is(err, pgx.ErrNoRows) && attempt == 0 {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+		rec, err := toRecord(row)
+		return rec, false, err
+	}
+}
+
+func (s *PostgresStore) Complete(ctx context.Context, id string, resp Response) error {
+	headers, err := json.Marshal(resp.Header)
+	if err != nil {
+		return fmt.Errorf("failed to encode response headers: %w", err)
+	}
+	status := int32(resp.Status)
+	n, err := s.q.CompleteIdempotencyKey(ctx, db.CompleteIdempotencyKeyParams{
+		ID:              id,
+		ResponseStatus:  &status,
+		ResponseHeaders: headers,
+		ResponseBody:    resp.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	if n == 0 {
+		return ErrNotClaimed
+	}
+	return nil
+}
+
+func (s *PostgresStore) Release(ctx context.Context, id string) error {
+	if err := s.q.ReleaseIdempotencyKey(ctx, id); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired deletes up to limit expired records. Run it periodically;
+// expired records are otherwise only replaced when their key is reused.
+func (s *PostgresStore) PurgeExpired(ctx context.Context, now time.Time, limit int) (int64, error) {
+	n, err := s.q.DeleteExpiredIdempotencyKeys(ctx, db.DeleteExpiredIdempotencyKeysParams{
+		ExpiresAt: now,
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+	return n, nil
+}
+
+func toRecord(row db.IdempotencyKey) (*Record, error) {
+	rec := &Record{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		Key:         row.IdempotencyKey,
+		Fingerprint: row.Fingerprint,
+		Body:        row.ResponseBody,
+		ExpiresAt:   row.ExpiresAt,
+		LockedUntil: row.LockedUntil,
+	}
+	if row.ResponseStatus != nil {
+		rec.Status = int(*row.ResponseStatus)
+	}
+	if len(row.ResponseHeaders) > 0 {
+		rec.Header = make(http.Header)
+		if err := json.Unmarshal(row.ResponseHeaders, &rec.Header); err != nil {
+			return nil, fmt.Errorf("failed to decode response headers: %w", err)
+		}
+	}
+	return rec, nil
+}
@@ -0,0 +1,36 @@
+-- internal/shared/idempotency/query.sql
+
+-- Inserts a new claim or takes over an expired or abandoned one. Returns no
+-- row when a live record exists.
+-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (id, user_id, idempotency_key, fingerprint, locked_until, expires_at)
+VALUES (@id, @user_id, @idempotency_key, @fingerprint, @locked_until, @expires_at)
+ON CONFLICT (user_id, idempotency_key) DO UPDATE
+SET id               = EXCLUDED.id,
+    fingerprint      = EXCLUDED.fingerprint,
+    response_status  = NULL,
+    response_headers = NULL,
+    response_body    = NULL,
+    locked_until     = EXCLUDED.locked_until,
+    expires_at       = EXCLUDED.expires_at,
+    created_at       = NOW()
+WHERE idempotency_keys.expires_at <= @now::timestamptz
+   OR (idempotency_keys.response_status IS NULL AND idempotency_keys.locked_until <= @now::timestamptz)
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2;
+
+-- name: CompleteIdempotencyKey :execrows
+UPDATE idempotency_keys
+SET response_status = $2, response_headers = $3, response_body = $4
+WHERE id = $1 AND response_status IS NULL;
+
+-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys WHERE id = $1 AND response_status IS NULL;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE id IN (
+    SELECT id FROM idempotency_keys WHERE expires_at <= $1 LIMIT $2
+);
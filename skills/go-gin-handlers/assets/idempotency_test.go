@@ -0,0 +1,190 @@
+// internal/shared/middleware/idempotency_test.go
+package middleware_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"api/booking/internal/shared/idempotency"
+	"api/booking/internal/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// newIdempotentRouter serves POST /bookings behind the middleware. Each call
+// to handler gets the 1-based call number.
+func newIdempotentRouter(store idempotency.Store, handler func(c *gin.Context, call int32)) (*gin.Engine, *atomic.Int32) {
+	gin.SetMode(gin.TestMode)
+	calls := new(atomic.Int32)
+	r := gin.New()
+	r.Use(gin.RecoveryWithWriter(io.Discard))
+	r.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			c.Set("user_id", user)
+		}
+	})
+	r.POST("/bookings", middleware.Idempotency(store, middleware.IdempotencyConfig{Required: true}), func(c *gin.Context) {
+		handler(c, calls.Add(1))
+	})
+	return r, calls
+}
+
+func created(c *gin.Context, call int32) {
+	c.Header("Location", "/bookings/"+strconv.Itoa(int(call)))
+	c.JSON(http.StatusCreated, gin.H{"id": call})
+}
+
+func post(r http.Handler, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/bookings", strings.NewReader(body))
+	req.Header.Set("X-Test-User", user)
+	if key != "" {
+		req.Header.Set(middleware.HeaderIdempotencyKey, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysTheStoredResponse(t *testing.T) {
+	r, calls := newIdempotentRouter(idempotency.NewInMemoryStore(), created)
+
+	first := post(r, "ana", "key-1", `{"pet":"rex"}`)
+	second := post(r, "ana", "key-1", `{"pet":"rex"}`)
+
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", calls.Load())
+	}
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	if got := second.Header().Get("Location"); got != "/bookings/1" {
+		t.Errorf("replayed Location = %q, want the stored header", got)
+	}
+	if second.Header().Get(middleware.HeaderIdempotentReplayed) != "true" || first.Header().Get(middleware.HeaderIdempotentReplayed) != "" {
+		t.Error("only the replay should carry Idempotent-Replayed")
+	}
+
+	// Keys are scoped to the user.
+	if w := post(r, "bob", "key-1", `{"pet":"rex"}`); w.Code != http.StatusCreated || calls.Load() != 2 {
+		t.Errorf("another user's request = %d after %d calls, want a fresh 201", w.Code, calls.Load())
+	}
+}
+
+func TestIdempotency_RejectsAKeyReusedForAnotherRequest(t *testing.T) {
+	r, calls := newIdempotentRouter(idempotency.NewInMemoryStore(), created)
+	post(r, "ana", "key-1", `{"pet":"rex"}`)
+
+	w := post(r, "ana", "key-1", `{"pet":"luna"}`)
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), "IDEMPOTENCY_KEY_REUSED") {
+		t.Errorf("reused key = %d %s, want 422 IDEMPOTENCY_KEY_REUSED", w.Code, w.Body)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotency_ConflictWhileInFlight(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	r, calls := newIdempotentRouter(idempotency.NewInMemoryStore(), func(c *gin.Context, call int32) {
+		close(entered)
+		<-release
+		created(c, call)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(r, "ana", "key-1", `{}`) }()
+	<-entered
+
+	w := post(r, "ana", "key-1", `{}`)
+	if w.Code != http.StatusConflict || w.Header().Get("Retry-After") != "1" {
+		t.Errorf("retry while in flight = %d, Retry-After %q; want 409 and 1", w.Code, w.Header().Get("Retry-After"))
+	}
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("first request = %d, want 201", first.Code)
+	}
+	if w := post(r, "ana", "key-1", `{}`); w.Header().Get(middleware.HeaderIdempotentReplayed) != "true" || calls.Load() != 1 {
+		t.Errorf("retry after completion: replayed %q after %d calls, want a replay", w.Header().Get(middleware.HeaderIdempotentReplayed), calls.Load())
+	}
+}
+
+func TestIdempotency_ReleasesTheKeyOnFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		fail func(c *gin.Context)
+	}{
+		{"5xx", func(c *gin.Context) { c.JSON(http.StatusServiceUnavailable, gin.H{"error": "try later"}) }},
+		{"panic", func(c *gin.Context) { panic("handler bug") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, calls := newIdempotentRouter(idempotency.NewInMemoryStore(), func(c *gin.Context, call int32) {
+				if call == 1 {
+					tt.fail(c)
+					return
+				}
+				created(c, call)
+			})
+
+			if w := post(r, "ana", "key-1", `{}`); w.Code < http.StatusInternalServerError {
+				t.Fatalf("first request = %d, want a 5xx", w.Code)
+			}
+			w := post(r, "ana", "key-1", `{}`)
+			if w.Code != http.StatusCreated || calls.Load() != 2 {
+				t.Errorf("retry = %d after %d calls, want the handler to run again", w.Code, calls.Load())
+			}
+		})
+	}
+}
+
+func TestIdempotency_TakesOverAnAbandonedClaim(t *testing.T) {
+	ctx := context.Background()
+	store := idempotency.NewInMemoryStore()
+	r, calls := newIdempotentRouter(store, created)
+
+	// A claim left by an instance that crashed two minutes ago; its lock
+	// (1m by default) has timed out.
+	_, claimed, err := store.Begin(ctx, idempotency.Claim{
+		UserID:      "ana",
+		Key:         "key-1",
+		Fingerprint: "from the crashed instance",
+		Now:         time.Now().Add(-2 * time.Minute),
+		TTL:         24 * time.Hour,
+		LockTimeout: time.Minute,
+	})
+	if err != nil || !claimed {
+		t.Fatalf("Begin() = %v, %v", claimed, err)
+	}
+
+	w := post(r, "ana", "key-1", `{}`)
+	if w.Code != http.StatusCreated || calls.Load() != 1 {
+		t.Errorf("request after the lock timed out = %d after %d calls, want 201", w.Code, calls.Load())
+	}
+	if w := post(r, "ana", "key-1", `{}`); w.Header().Get(middleware.HeaderIdempotentReplayed) != "true" {
+		t.Error("response of the takeover was not stored")
+	}
+}
+
+func TestIdempotency_RequiresTheHeader(t *testing.T) {
+	r, calls := newIdempotentRouter(idempotency.NewInMemoryStore(), created)
+	tests := []struct {
+		name, key string
+	}{
+		{"missing", ""},
+		{"too long", strings.Repeat("k", 256)},
+	}
+	for _, tt := range tests {
+		if w := post(r, "ana", tt.key, `{}`); w.Code != http.StatusBadRequest {
+			t.Errorf("%s key = %d, want 400", tt.name, w.Code)
+		}
+	}
+	if calls.Load() != 0 {
+		t.Errorf("handler ran %d times, want 0", calls.Load())
+	}
+}
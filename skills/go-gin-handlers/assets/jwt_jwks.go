@@ -0,0 +1,155 @@
+// internal/shared/auth/jwtauth/jwks.go
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type JWKSConfig struct {
+	URL        string
+	HTTPClient *http.Client  // Default: 10s timeout
+	TTL        time.Duration // Refresh cached keys after this; default 10m
+	// MinRefreshInterval limits refetches triggered by unknown kids, so
+	// tokens with made-up kids can't hammer the issuer. Default 1m.
+	MinRefreshInterval time.Duration
+}
+
+// JWKS fetches keys from an issuer's JWKS endpoint and caches them.
+//
+// Rotation: the issuer publishes a new key before signing with it. A token
+// with a kid we haven't seen triggers an early refresh (at most once per
+// MinRefreshInterval); retired keys disappear at the next refresh. If a
+// refresh fails, the cached keys keep working until one succeeds.
+type JWKS struct {
+	cfg   JWKSConfig
+	now   func() time.Time
+	fetch singleflight.Group
+
+	mu          sync.Mutex // Guards the cache only; never held during a fetch
+	keys        StaticKeys
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	fetching    bool
+}
+
+func NewJWKS(cfg JWKSConfig) *JWKS {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.TTL == 0 {
+		cfg.TTL = 10 * time.Minute
+	}
+	if cfg.MinRefreshInterval == 0 {
+		cfg.MinRefreshInterval = time.Minute
+	}
+	return &JWKS{cfg: cfg, now: time.Now}
+}
+
+// Key answers from the cache whenever it can. A stale cache is refreshed
+// in the background while its keys keep serving; only a missing key (first
+// use, or a kid from a rotation) waits for the fetch, and concurrent
+// callers share that one fetch.
+func (j *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	j.mu.Lock()
+	keys := j.keys
+	_, known := keys[kid]
+	if kid == "" {
+		known = len(keys) == 1
+	}
+	now := j.now()
+	stale := now.Sub(j.fetchedAt) >= j.cfg.TTL
+	canFetch := j.fetching || now.Sub(j.lastAttempt) >= j.cfg.MinRefreshInterval
+	j.mu.Unlock()
+
+	switch {
+	case known && !stale, !canFetch:
+		return lookup(keys, kid)
+	case known:
+		j.startRefresh(ctx)
+		return lookup(keys, kid)
+	}
+
+	select {
+	case res := <-j.startRefresh(ctx):
+		if res.Err != nil && keys == nil {
+			return nil, res.Err
+		}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	j.mu.Lock()
+	keys = j.keys
+	j.mu.Unlock()
+	return lookup(keys, kid)
+}
+
+// startRefresh joins the fetch in flight or starts one. The fetch isn't
+// tied to the caller's request, which may end first; the HTTP client's
+// timeout bounds it.
+func (j *JWKS) startRefresh(ctx context.Context) <-chan singleflight.Result {
+	ctx = context.WithoutCancel(ctx)
+	return j.fetch.DoChan("jwks", func() (any, error) {
+		j.mu.Lock()
+		now := j.now()
+		if now.Sub(j.lastAttempt) < j.cfg.MinRefreshInterval {
+			j.mu.Unlock()
+			return nil, nil // A fetch just finished; callers re-read the cache
+		}
+		j.lastAttempt, j.fetching = now, true
+		j.mu.Unlock()
+
+		keys, err := j.get(ctx)
+
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		j.fetching = false
+		if err != nil {
+			if j.keys != nil {
+				slog.WarnContext(ctx, "failed to refresh JWKS, using cached keys", "error", err, "url", j.cfg.URL)
+			}
+			return nil, err
+		}
+		j.keys, j.fetchedAt = keys, j.now()
+		return nil, nil
+	})
+}
+
+func (j *JWKS) get(ctx context.Context) (StaticKeys, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.cfg.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build JWKS request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS has no usable signing keys")
+	}
+	return keys, nil
+}
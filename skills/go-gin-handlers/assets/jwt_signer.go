@@ -0,0 +1,164 @@
+// internal/shared/auth/jwtauth/signer.go
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"api/auth/internal/shared/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type SignerConfig struct {
+	KeyID    string
+	Key      crypto.Signer // *rsa.PrivateKey, *ecdsa.PrivateKey (P-256) or ed25519.PrivateKey
+	Issuer   string
+	Audience string
+	TTL      time.Duration // Access token lifetime; default 15m
+	// Previous are retired public keys still published in the JWKS, so
+	// tokens they signed keep validating until they expire.
+	Previous StaticKeys
+}
+
+// Signer issues access tokens and serves the matching JWKS. Only the auth
+// service has one; every other service validates with a JWKS Validator.
+type Signer struct {
+	cfg    SignerConfig
+	method jwt.SigningMethod
+	jwks   []byte
+	now    func() time.Time
+}
+
+func NewSigner(cfg SignerConfig) (*Signer, error) {
+	if cfg.KeyID == "" || cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("signer key ID, issuer and audience are required")
+	}
+	if cfg.TTL == 0 {
+		cfg.TTL = 15 * time.Minute
+	}
+
+	var method jwt.SigningMethod
+	switch k := cfg.Key.(type) {
+	case *rsa.PrivateKey:
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, errors.New("only P-256 ECDSA keys are supported")
+		}
+		method = jwt.SigningMethodES256
+	case ed25519.PrivateKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported signing key %T", cfg.Key)
+	}
+
+	keys := StaticKeys{cfg.KeyID: cfg.Key.Public()}
+	for kid, pub := range cfg.Previous {
+		keys[kid] = pub
+	}
+	jwks, err := MarshalJWKS(keys)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{cfg: cfg, method: method, jwks: jwks, now: time.Now}, nil
+}
+
+// Sign issues an access token for claims.
+func (s *Signer) Sign(claims auth.Claims) (string, time.Time, error) {
+	now := s.now()
+	exp := now.Add(s.cfg.TTL)
+	tok := jwt.NewWithClaims(s.method, tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    s.cfg.Issuer,
+			Audience:  jwt.ClaimStrings{s.cfg.Audience},
+			Subject:   claims.UserID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+		Role:  claims.Role,
+		Scope: strings.Join(claims.Scopes, " "),
+	})
+	tok.Header["kid"] = s.cfg.KeyID
+
+	signed, err := tok.SignedString(s.cfg.Key)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return signed, exp, nil
+}
+
+// ServeJWKS publishes the current and previous public keys, e.g. at
+// GET /.well-known/jwks.json.
+func (s *Signer) ServeJWKS(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Write(s.jwks)
+}
+
+// LoadSigningKeyFile reads a PKCS#8 "PRIVATE KEY" PEM.
+func LoadSigningKeyFile(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("signing key must be a PKCS#8 PRIVATE KEY PEM")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key %T", key)
+	}
+	return signer, nil
+}
+
+// MarshalJWKS encodes public keys as a JWKS document.
+func MarshalJWKS(keys StaticKeys) ([]byte, error) {
+	b64 := base64.RawURLEncoding.EncodeToString
+	set := struct {
+		Keys []JWK `json:"keys"`
+	}{Keys: []JWK{}}
+	for kid, pub := range keys {
+		jwk := JWK{Kid: kid, Use: "sig"}
+		switch k := pub.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty, jwk.Alg = "RSA", "RS256"
+			jwk.N, jwk.E = b64(k.N.Bytes()), b64(big.NewInt(int64(k.E)).Bytes())
+		case *ecdsa.PublicKey:
+			raw, err := k.Bytes()
+			if err != nil {
+				return nil, fmt.Errorf("invalid EC key %q: %w", kid, err)
+			}
+			jwk.Kty, jwk.Alg, jwk.Crv = "EC", "ES256", "P-256"
+			jwk.X, jwk.Y = b64(raw[1:33]), b64(raw[33:])
+		case ed25519.PublicKey:
+			jwk.Kty, jwk.Alg, jwk.Crv = "OKP", "EdDSA", "Ed25519"
+			jwk.X = b64(k)
+		default:
+			return nil, fmt.Errorf("unsupported public key %T for %q", pub, kid)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return json.Marshal(set)
+}
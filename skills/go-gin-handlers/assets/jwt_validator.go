@@ -0,0 +1,121 @@
+// internal/shared/auth/jwtauth/validator.go
+package jwtauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"api/booking/internal/shared/auth"
+	"api/booking/internal/shared/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Config struct {
+	Issuer   string // Required "iss"
+	Audience string // Required to appear in "aud"
+	// Algorithms accepted in the token header. Default RS256, ES256, EdDSA;
+	// "none" and HMAC algorithms are never accepted.
+	Algorithms []string
+	ClockSkew  time.Duration // Leeway for exp/nbf/iat; default 30s
+}
+
+// Validator implements auth.TokenValidator for JWTs signed by keys from a
+// KeySource (JWKS endpoint, JWKS file or PEM).
+type Validator struct {
+	keys   KeySource
+	parser *jwt.Parser
+}
+
+func NewValidator(keys KeySource, cfg Config) (*Validator, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("jwt issuer and audience are required")
+	}
+	if len(cfg.Algorithms) == 0 {
+		cfg.Algorithms = []string{"RS256", "ES256", "EdDSA"}
+	}
+	for _, alg := range cfg.Algorithms {
+		if alg != "RS256" && alg != "ES256" && alg != "EdDSA" {
+			return nil, fmt.Errorf("unsupported jwt algorithm %q", alg)
+		}
+	}
+	if cfg.ClockSkew == 0 {
+		cfg.ClockSkew = 30 * time.Second
+	}
+
+	return &Validator{
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(cfg.Algorithms),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithLeeway(cfg.ClockSkew),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+		),
+	}, nil
+}
+
+// NewTokenValidator builds the validator from config: a JWKS URL in
+// deployed environments, a JWKS or PEM file locally.
+func NewTokenValidator(cfg config.AuthConfig) (*Validator, error) {
+	var keys KeySource
+	switch {
+	case cfg.JWKSURL != "":
+		keys = NewJWKS(JWKSConfig{URL: cfg.JWKSURL, TTL: cfg.JWKSCacheTTL})
+	case strings.HasSuffix(cfg.PublicKeyFile, ".json"):
+		static, err := LoadJWKSFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = static
+	case cfg.PublicKeyFile != "":
+		static, err := LoadPEMFile(cfg.PublicKeyFile, "")
+		if err != nil {
+			return nil, err
+		}
+		keys = static
+	default:
+		return nil, errors.New("AUTH_JWKS_URL or AUTH_PUBLIC_KEY_FILE is required")
+	}
+	return NewValidator(keys, Config{
+		Issuer:    cfg.Issuer,
+		Audience:  cfg.Audience,
+		ClockSkew: cfg.ClockSkew,
+	})
+}
+
+// tokenClaims are the claims we read. Scopes come from the OAuth "scope"
+// string (space-separated) or an "scp" array, whichever the issuer uses.
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Role  string   `json:"role"`
+	Scope string   `json:"scope"`
+	Scp   []string `json:"scp"`
+}
+
+func (v *Validator) Validate(ctx context.Context, token string) (*auth.Claims, error) {
+	var claims tokenClaims
+	_, err := v.parser.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", auth.ErrInvalidToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", auth.ErrInvalidToken)
+	}
+
+	scopes := claims.Scp
+	if claims.Scope != "" {
+		scopes = strings.Fields(claims.Scope)
+	}
+	return &auth.Claims{
+		UserID: claims.Subject,
+		Role:   claims.Role,
+		Scopes: scopes,
+	}, nil
+}
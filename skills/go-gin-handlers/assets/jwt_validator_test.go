@@ -0,0 +1,440 @@
+// internal/shared/auth/jwtauth/validator_test.go
+package jwtauth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"api/booking/internal/shared/auth"
+	"api/booking/internal/shared/auth/jwtauth"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	issuer   = "https://auth.test"
+	audience = "bastet-api"
+)
+
+type signingKey struct {
+	kid    string
+	method jwt.SigningMethod
+	priv   crypto.Signer
+}
+
+func newRSAKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingKey{kid, jwt.SigningMethodRS256, priv}
+}
+
+func newECKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingKey{kid, jwt.SigningMethodES256, priv}
+}
+
+func newEdKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingKey{kid, jwt.SigningMethodEdDSA, priv}
+}
+
+func (k signingKey) jwk() map[string]string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch pub := k.priv.Public().(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kty": "RSA", "kid": k.kid, "use": "sig", "n": b64(pub.N.Bytes()), "e": b64([]byte{1, 0, 1})}
+	case *ecdsa.PublicKey:
+		raw, _ := pub.Bytes()
+		return map[string]string{"kty": "EC", "kid": k.kid, "crv": "P-256", "x": b64(raw[1:33]), "y": b64(raw[33:])}
+	case ed25519.PublicKey:
+		return map[string]string{"kty": "OKP", "kid": k.kid, "crv": "Ed25519", "x": b64(pub)}
+	}
+	panic("unsupported key")
+}
+
+func (k signingKey) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(k.method, claims)
+	if k.kid != "" {
+		tok.Header["kid"] = k.kid
+	}
+	s, err := tok.SignedString(k.priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":   issuer,
+		"aud":   audience,
+		"sub":   "user-1",
+		"role":  "owner",
+		"scope": "bookings:read bookings:write",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+}
+
+// jwksServer is a local issuer whose key set can be rotated mid-test.
+type jwksServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	keys  []signingKey
+	down  atomic.Bool
+	fetch atomic.Int32
+	hits  chan struct{} // One value per request, so tests can wait for background fetches
+	hang  chan struct{} // While open (non-nil), requests block until it is closed
+}
+
+func newJWKSServer(t *testing.T, keys ...signingKey) *jwksServer {
+	s := &jwksServer{keys: keys, hits: make(chan struct{}, 100)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetch.Add(1)
+		s.hits <- struct{}{}
+		s.mu.Lock()
+		hang := s.hang
+		s.mu.Unlock()
+		if hang != nil {
+			<-hang
+		}
+		if s.down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		set := map[string]any{"keys": []map[string]string{}}
+		for _, k := range s.keys {
+			set["keys"] = append(set["keys"].([]map[string]string), k.jwk())
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) setKeys(keys ...signingKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+// waitForFetch waits for the JWKS server to see a request.
+func (s *jwksServer) waitForFetch(t *testing.T) {
+	t.Helper()
+	select {
+	case <-s.hits:
+	case <-time.After(5 * time.Second):
+		t.Fatal("JWKS never fetched")
+	}
+}
+
+func newValidator(t *testing.T, keys jwtauth.KeySource) *jwtauth.Validator {
+	t.Helper()
+	v, err := jwtauth.NewValidator(keys, jwtauth.Config{Issuer: issuer, Audience: audience, ClockSkew: 30 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestValidate_Algorithms(t *testing.T) {
+	keys := []signingKey{newRSAKey(t, "rsa-1"), newECKey(t, "ec-1"), newEdKey(t, "ed-1")}
+	srv := newJWKSServer(t, keys...)
+	v := newValidator(t, jwtauth.NewJWKS(jwtauth.JWKSConfig{URL: srv.URL}))
+
+	for _, k := range keys {
+		t.Run(k.method.Alg(), func(t *testing.T) {
+			claims, err := v.Validate(context.Background(), k.sign(t, validClaims()))
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			want := auth.Claims{UserID: "user-1", Role: "owner", Scopes: []string{"bookings:read", "bookings:write"}}
+			if claims.UserID != want.UserID || claims.Role != want.Role || !slices.Equal(claims.Scopes, want.Scopes) {
+				t.Errorf("claims = %+v, want %+v", *claims, want)
+			}
+		})
+	}
+	if n := srv.fetch.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1 (cached)", n)
+	}
+}
+
+func TestValidate_ScpArray(t *testing.T) {
+	key := newEdKey(t, "ed-1")
+	v := newValidator(t, newJWKSKeys(t, key))
+
+	c := validClaims()
+	delete(c, "scope")
+	c["scp"] = []string{"admin"}
+	claims, err := v.Validate(context.Background(), key.sign(t, c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !claims.HasScope("admin") {
+		t.Errorf("scopes = %v, want [admin]", claims.Scopes)
+	}
+}
+
+func TestValidate_Rejects(t *testing.T) {
+	key := newRSAKey(t, "rsa-1")
+	v := newValidator(t, newJWKSKeys(t, key))
+	now := time.Now()
+
+	with := func(k string, val any) jwt.MapClaims {
+		c := validClaims()
+		if val == nil {
+			delete(c, k)
+		} else {
+			c[k] = val
+		}
+		return c
+	}
+	other := newRSAKey(t, "rsa-1") // Same kid, different key
+
+	cases := map[string]string{
+		"expired":          key.sign(t, with("exp", now.Add(-time.Minute).Unix())),
+		"not yet valid":    key.sign(t, with("nbf", now.Add(time.Minute).Unix())),
+		"missing exp":      key.sign(t, with("exp", nil)),
+		"missing sub":      key.sign(t, with("sub", nil)),
+		"wrong issuer":     key.sign(t, with("iss", "https://evil.test")),
+		"wrong audience":   key.sign(t, with("aud", "other-api")),
+		"unknown kid":      signingKey{"rsa-2", key.method, key.priv}.sign(t, validClaims()),
+		"bad signature":    other.sign(t, validClaims()),
+		"alg none":         unsigned(t, validClaims()),
+		"HS256 public key": hmacWithPublicKey(t, key),
+		"garbage":          "not.a.jwt",
+	}
+	for name, token := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := v.Validate(context.Background(), token)
+			if !errors.Is(err, auth.ErrInvalidToken) {
+				t.Errorf("err = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestValidate_ClockSkew(t *testing.T) {
+	key := newECKey(t, "ec-1")
+	v := newValidator(t, newJWKSKeys(t, key))
+	now := time.Now()
+
+	c := validClaims()
+	c["exp"] = now.Add(-10 * time.Second).Unix()
+	c["nbf"] = now.Add(10 * time.Second).Unix()
+	if _, err := v.Validate(context.Background(), key.sign(t, c)); err != nil {
+		t.Errorf("token within 30s skew rejected: %v", err)
+	}
+}
+
+func TestJWKS_Rotation(t *testing.T) {
+	oldKey, newKey := newRSAKey(t, "2025-01"), newRSAKey(t, "2025-02")
+	srv := newJWKSServer(t, oldKey)
+	v := newValidator(t, jwtauth.NewJWKS(jwtauth.JWKSConfig{URL: srv.URL, MinRefreshInterval: time.Nanosecond}))
+	ctx := context.Background()
+
+	if _, err := v.Validate(ctx, oldKey.sign(t, validClaims())); err != nil {
+		t.Fatal(err)
+	}
+
+	// The issuer publishes the new key next to the old one, then signs with it.
+	srv.setKeys(oldKey, newKey)
+	if _, err := v.Validate(ctx, newKey.sign(t, validClaims())); err != nil {
+		t.Fatalf("new key not picked up: %v", err)
+	}
+	if _, err := v.Validate(ctx, oldKey.sign(t, validClaims())); err != nil {
+		t.Fatalf("old key rejected during overlap: %v", err)
+	}
+
+	// Retiring the old key takes effect on the next refresh, here forced by
+	// an unknown kid.
+	srv.setKeys(newKey)
+	if _, err := v.Validate(ctx, signingKey{"2025-03", newKey.method, newKey.priv}.sign(t, validClaims())); err == nil {
+		t.Fatal("unknown kid accepted")
+	}
+	if _, err := v.Validate(ctx, oldKey.sign(t, validClaims())); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("retired key: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestJWKS_UnknownKidRefreshIsRateLimited(t *testing.T) {
+	key := newRSAKey(t, "rsa-1")
+	srv := newJWKSServer(t, key)
+	v := newValidator(t, jwtauth.NewJWKS(jwtauth.JWKSConfig{URL: srv.URL, MinRefreshInterval: time.Hour}))
+
+	for i := range 5 {
+		forged := signingKey{"made-up", key.method, key.priv}
+		forged.kid += string(rune('a' + i))
+		v.Validate(context.Background(), forged.sign(t, validClaims()))
+	}
+	if n := srv.fetch.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", n)
+	}
+}
+
+func TestJWKS_KeepsCachedKeysWhenIssuerIsDown(t *testing.T) {
+	key := newEdKey(t, "ed-1")
+	srv := newJWKSServer(t, key)
+	v := newValidator(t, jwtauth.NewJWKS(jwtauth.JWKSConfig{URL: srv.URL, TTL: time.Nanosecond, MinRefreshInterval: time.Nanosecond}))
+	ctx := context.Background()
+
+	if _, err := v.Validate(ctx, key.sign(t, validClaims())); err != nil {
+		t.Fatal(err)
+	}
+	srv.waitForFetch(t)
+	srv.down.Store(true)
+	if _, err := v.Validate(ctx, key.sign(t, validClaims())); err != nil {
+		t.Errorf("cached key not used while issuer is down: %v", err)
+	}
+	srv.waitForFetch(t) // The stale cache triggered a refresh
+}
+
+func TestJWKS_StaleKeysDontWaitForTheIssuer(t *testing.T) {
+	key, newKey := newEdKey(t, "ed-1"), newEdKey(t, "ed-2")
+	srv := newJWKSServer(t, key)
+	v := newValidator(t, jwtauth.NewJWKS(jwtauth.JWKSConfig{URL: srv.URL, TTL: time.Nanosecond, MinRefreshInterval: time.Nanosecond}))
+	ctx := context.Background()
+
+	if _, err := v.Validate(ctx, key.sign(t, validClaims())); err != nil {
+		t.Fatal(err)
+	}
+	srv.waitForFetch(t)
+
+	// The issuer hangs: the refresh it triggers must not hold up
+	// validations that the cached key can answer.
+	hang := make(chan struct{})
+	srv.mu.Lock()
+	srv.hang = hang
+	srv.keys = []signingKey{key, newKey}
+	srv.mu.Unlock()
+
+	done := make(chan error)
+	go func() {
+		for range 20 {
+			if _, err := v.Validate(ctx, key.sign(t, validClaims())); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Validate during refresh: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Validate blocked on the JWKS refresh")
+	}
+	srv.waitForFetch(t)
+	if n := srv.fetch.Load(); n != 2 {
+		t.Errorf("JWKS fetched %d times, want 2 (stale callers share one refresh)", n)
+	}
+
+	// Tokens signed with a key we don't have yet wait for the fetch in
+	// flight rather than failing.
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := v.Validate(ctx, newKey.sign(t, validClaims()))
+			errs <- err
+		}()
+	}
+	close(hang)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("new key after refresh: %v", err)
+		}
+	}
+}
+
+func TestStaticKeys_PEM(t *testing.T) {
+	key := newECKey(t, "")
+	der, err := x509.MarshalPKIXPublicKey(key.priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := jwtauth.ParsePEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := newValidator(t, jwtauth.StaticKeys{"": pub})
+	if _, err := v.Validate(context.Background(), key.sign(t, validClaims())); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+// newJWKSKeys parses the keys' JWKS, like a JWKS file would be loaded.
+func newJWKSKeys(t *testing.T, keys ...signingKey) jwtauth.StaticKeys {
+	t.Helper()
+	set := map[string]any{"keys": []map[string]string{}}
+	for _, k := range keys {
+		set["keys"] = append(set["keys"].([]map[string]string), k.jwk())
+	}
+	data, _ := json.Marshal(set)
+	static, err := jwtauth.ParseJWKS(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return static
+}
+
+func unsigned(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// hmacWithPublicKey is the classic algorithm-confusion attack: an HS256
+// token whose secret is the issuer's public key.
+func hmacWithPublicKey(t *testing.T, key signingKey) string {
+	t.Helper()
+	der, _ := x509.MarshalPKIXPublicKey(key.priv.Public())
+	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+	tok.Header["kid"] = key.kid
+	s, err := tok.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
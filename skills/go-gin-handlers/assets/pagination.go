@@ -0,0 +1,134 @@
+// internal/shared/pagination/pagination.go
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+
+	"api/booking/internal/shared/apperror"
+)
+
+// Limits bound per_page. Handlers may pass tighter limits for expensive
+// resources.
+type Limits struct {
+	Default int
+	Max     int
+}
+
+var DefaultLimits = Limits{Default: 20, Max: 100}
+
+// ErrInvalidCursor is a 400 catalog error, so repositories can return it
+// as is when a cursor decodes but doesn't point at a valid position.
+var ErrInvalidCursor = apperror.New(apperror.CodeValidation, "INVALID_PAGINATION", "Invalid pagination parameters").
+	WithField("cursor", "is invalid or expired")
+
+// Offset is a page/per_page request. Simple and supports totals, but pages
+// shift when rows are inserted and deep pages get slow; prefer Keyset for
+// feeds and large tables.
+type Offset struct {
+	Page    int // 1-based
+	PerPage int
+}
+
+func (o Offset) Limit() int  { return o.PerPage }
+func (o Offset) Offset() int { return (o.Page - 1) * o.PerPage }
+
+// OffsetPage is what a repository returns for an Offset request.
+type OffsetPage[T any] struct {
+	Items []T
+	Total int
+}
+
+func (p OffsetPage[T]) TotalPages(perPage int) int {
+	if perPage <= 0 {
+		return 0
+	}
+	return (p.Total + perPage - 1) / perPage
+}
+
+// Cursor marks a position in a keyset ordering: the sort key of the
+// boundary row plus its ID as tie-breaker. It is opaque to clients but not
+// secret — never put anything in it that authorizes access.
+type Cursor struct {
+	Key      string `json:"k"`           // Sort column value, e.g. RFC 3339 timestamp
+	ID       string `json:"id"`          // Tie-breaker
+	Backward bool   `json:"b,omitempty"` // Page before this position
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Keyset asks for Limit rows after Cursor (or before it when
+// Cursor.Backward). A nil Cursor is the first page.
+//
+// Repositories fetch Limit+1 rows — in reverse order when Backward — and
+// hand them to NewKeysetPage, which trims the extra row and builds cursors:
+//
+//	WHERE (created_at, id) > ($1, $2) ORDER BY created_at, id LIMIT $3      -- forward
+//	WHERE (created_at, id) < ($1, $2) ORDER BY created_at DESC, id DESC ... -- backward
+type Keyset struct {
+	Cursor *Cursor
+	Limit  int
+}
+
+func (k Keyset) Backward() bool { return k.Cursor != nil && k.Cursor.Backward }
+
+// FetchLimit is the number of rows to query: one extra to detect more pages.
+func (k Keyset) FetchLimit() int { return k.Limit + 1 }
+
+type KeysetPage[T any] struct {
+	Items []T
+	Next  *Cursor // nil on the last page
+	Prev  *Cursor // nil on the first page
+}
+
+// NewKeysetPage builds a page from up to FetchLimit rows in query order.
+// cursorOf returns the position of an item.
+func NewKeysetPage[T any](rows []T, req Keyset, cursorOf func(T) Cursor) KeysetPage[T] {
+	more := len(rows) > req.Limit
+	if more {
+		rows = rows[:req.Limit]
+	}
+	if req.Backward() {
+		rows = slices.Clone(rows)
+		slices.Reverse(rows)
+	}
+
+	page := KeysetPage[T]{Items: rows}
+	if len(rows) == 0 {
+		return page
+	}
+
+	// Going forward, more rows mean a next page and a cursor means we came
+	// from somewhere; going backward it's the other way round.
+	hasNext, hasPrev := more, req.Cursor != nil
+	if req.Backward() {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		c := cursorOf(rows[len(rows)-1])
+		c.Backward = false
+		page.Next = &c
+	}
+	if hasPrev {
+		c := cursorOf(rows[0])
+		c.Backward = true
+		page.Prev = &c
+	}
+	return page
+}
@@ -0,0 +1,130 @@
+// internal/shared/server/pagination.go
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"api/booking/internal/shared/apperror"
+	"api/booking/internal/shared/pagination"
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidPagination = apperror.New(apperror.CodeValidation, "INVALID_PAGINATION", "Invalid pagination parameters")
+
+// ParseOffset reads ?page=&per_page=. Missing values take defaults;
+// out-of-range values are rejected rather than clamped, so clients notice.
+func ParseOffset(c *gin.Context, limits pagination.Limits) (pagination.Offset, error) {
+	page, err := intQuery(c, "page", 1, 1, 0)
+	if err != nil {
+		return pagination.Offset{}, err
+	}
+	perPage, err := intQuery(c, "per_page", limits.Default, 1, limits.Max)
+	if err != nil {
+		return pagination.Offset{}, err
+	}
+	if c.Query("cursor") != "" {
+		return pagination.Offset{}, errInvalidPagination.WithField("cursor", "not supported on this endpoint; use page")
+	}
+	return pagination.Offset{Page: page, PerPage: perPage}, nil
+}
+
+// ParseKeyset reads ?cursor=&per_page=. An absent cursor is the first page.
+func ParseKeyset(c *gin.Context, limits pagination.Limits) (pagination.Keyset, error) {
+	perPage, err := intQuery(c, "per_page", limits.Default, 1, limits.Max)
+	if err != nil {
+		return pagination.Keyset{}, err
+	}
+	if c.Query("page") != "" {
+		return pagination.Keyset{}, errInvalidPagination.WithField("page", "not supported on this endpoint; use cursor")
+	}
+
+	req := pagination.Keyset{Limit: perPage}
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := pagination.DecodeCursor(raw)
+		if err != nil {
+			return pagination.Keyset{}, err
+		}
+		req.Cursor = cursor
+	}
+	return req, nil
+}
+
+// OKWithOffsetPage writes items with page/per_page/total meta and
+// first/prev/next/last Link headers (RFC 8288).
+func OKWithOffsetPage[T any](c *gin.Context, req pagination.Offset, page pagination.OffsetPage[T]) {
+	totalPages := page.TotalPages(req.PerPage)
+
+	links := []string{link(c, "first", map[string]string{"page": "1"})}
+	if req.Page > 1 {
+		links = append(links, link(c, "prev", map[string]string{"page": strconv.Itoa(min(req.Page-1, max(totalPages, 1)))}))
+	}
+	if req.Page < totalPages {
+		links = append(links, link(c, "next", map[string]string{"page": strconv.Itoa(req.Page + 1)}))
+	}
+	links = append(links, link(c, "last", map[string]string{"page": strconv.Itoa(max(totalPages, 1))}))
+	c.Header("Link", strings.Join(links, ", "))
+
+	OKWithMeta(c, http.StatusOK, nonNil(page.Items), Meta{
+		Page:       req.Page,
+		PerPage:    req.PerPage,
+		Total:      page.Total,
+		TotalPages: totalPages,
+	})
+}
+
+// OKWithKeysetPage writes items with next/prev cursors in meta and the
+// matching Link headers.
+func OKWithKeysetPage[T any](c *gin.Context, req pagination.Keyset, page pagination.KeysetPage[T]) {
+	meta := Meta{PerPage: req.Limit}
+	var links []string
+	if page.Next != nil {
+		meta.NextCursor = page.Next.Encode()
+		links = append(links, link(c, "next", map[string]string{"cursor": meta.NextCursor}))
+	}
+	if page.Prev != nil {
+		meta.PrevCursor = page.Prev.Encode()
+		links = append(links, link(c, "prev", map[string]string{"cursor": meta.PrevCursor}))
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+
+	OKWithMeta(c, http.StatusOK, nonNil(page.Items), meta)
+}
+
+// link keeps the request's other query params (filters, sort) and
+// overrides the given ones. The target is relative to the request URI.
+func link(c *gin.Context, rel string, set map[string]string) string {
+	q := c.Request.URL.Query()
+	for k, v := range set {
+		q.Set(k, v)
+	}
+	return fmt.Sprintf(`<%s?%s>; rel="%s"`, c.Request.URL.Path, q.Encode(), rel)
+}
+
+func intQuery(c *gin.Context, name string, def, lo, hi int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < lo || (hi > 0 && n > hi) {
+		desc := fmt.Sprintf("must be an integer ≥ %d", lo)
+		if hi > 0 {
+			desc = fmt.Sprintf("must be an integer between %d and %d", lo, hi)
+		}
+		return 0, errInvalidPagination.WithField(name, desc)
+	}
+	return n, nil
+}
+
+// nonNil makes empty pages render as [] instead of null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
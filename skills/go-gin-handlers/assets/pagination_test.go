@@ -0,0 +1,120 @@
+// internal/shared/pagination/pagination_test.go
+package pagination_test
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"api/booking/internal/shared/pagination"
+)
+
+func cursorOf(n int) pagination.Cursor {
+	return pagination.Cursor{Key: strconv.Itoa(n), ID: "id-" + strconv.Itoa(n)}
+}
+
+func TestNewKeysetPage(t *testing.T) {
+	at := func(n int, backward bool) *pagination.Cursor {
+		c := cursorOf(n)
+		c.Backward = backward
+		return &c
+	}
+
+	tests := []struct {
+		name      string
+		rows      []int // As the repository returns them, in query order
+		req       pagination.Keyset
+		wantItems []int
+		wantNext  *pagination.Cursor
+		wantPrev  *pagination.Cursor
+	}{
+		{
+			name:      "first page with more",
+			rows:      []int{1, 2, 3, 4},
+			req:       pagination.Keyset{Limit: 3},
+			wantItems: []int{1, 2, 3},
+			wantNext:  at(3, false),
+		},
+		{
+			name:      "only page",
+			rows:      []int{1, 2},
+			req:       pagination.Keyset{Limit: 3},
+			wantItems: []int{1, 2},
+		},
+		{
+			name:      "middle page forward",
+			rows:      []int{4, 5, 6, 7},
+			req:       pagination.Keyset{Limit: 3, Cursor: at(3, false)},
+			wantItems: []int{4, 5, 6},
+			wantNext:  at(6, false),
+			wantPrev:  at(4, true),
+		},
+		{
+			name:      "last page forward",
+			rows:      []int{7, 8, 9},
+			req:       pagination.Keyset{Limit: 3, Cursor: at(6, false)},
+			wantItems: []int{7, 8, 9},
+			wantPrev:  at(7, true),
+		},
+		{
+			name:      "middle page backward",
+			rows:      []int{6, 5, 4, 3},
+			req:       pagination.Keyset{Limit: 3, Cursor: at(7, true)},
+			wantItems: []int{4, 5, 6},
+			wantNext:  at(6, false),
+			wantPrev:  at(4, true),
+		},
+		{
+			name:      "back to the first page",
+			rows:      []int{3, 2, 1},
+			req:       pagination.Keyset{Limit: 3, Cursor: at(4, true)},
+			wantItems: []int{1, 2, 3},
+			wantNext:  at(3, false),
+		},
+		{
+			name: "empty",
+			req:  pagination.Keyset{Limit: 3, Cursor: at(9, false)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := slices.Clone(tt.rows)
+			page := pagination.NewKeysetPage(rows, tt.req, cursorOf)
+
+			if !slices.Equal(page.Items, tt.wantItems) {
+				t.Errorf("Items = %v, want %v", page.Items, tt.wantItems)
+			}
+			if !equalCursor(page.Next, tt.wantNext) {
+				t.Errorf("Next = %+v, want %+v", page.Next, tt.wantNext)
+			}
+			if !equalCursor(page.Prev, tt.wantPrev) {
+				t.Errorf("Prev = %+v, want %+v", page.Prev, tt.wantPrev)
+			}
+			if !slices.Equal(rows, tt.rows) {
+				t.Errorf("NewKeysetPage() modified the caller's rows: %v", rows)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	want := pagination.Cursor{Key: "2026-01-01T09:00:00Z", ID: "3f2a9c1e", Backward: true}
+	got, err := pagination.DecodeCursor(want.Encode())
+	if err != nil || *got != want {
+		t.Errorf("DecodeCursor(Encode()) = %+v, %v; want %+v", got, err, want)
+	}
+
+	for _, s := range []string{"", "not base64!", "bm90IGpzb24", pagination.Cursor{Key: "k"}.Encode()} {
+		if _, err := pagination.DecodeCursor(s); !errors.Is(err, pagination.ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", s, err)
+		}
+	}
+}
+
+func equalCursor(a, b *pagination.Cursor) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,86 @@
+// internal/shared/server/problem.go
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"api/booking/internal/shared/observability"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	MediaTypeJSON    = "application/json"
+	MediaTypeProblem = "application/problem+json"
+)
+
+// ProblemTypeBase prefixes the problem "type" URI. A relative reference is
+// allowed by RFC 9457 and resolves against the API host, e.g.
+// /problems/not-found. Point it at published docs when they exist.
+var ProblemTypeBase = "/problems/"
+
+// Problem is an RFC 9457 problem details object. Members after Instance are
+// extensions: Code matches the envelope's error.code so both formats can be
+// handled by the same client logic.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	Code      string            `json:"code"`
+	TraceID   string            `json:"trace_id,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+	Reason    string            `json:"reason,omitempty"`
+	Errors    any               `json:"errors,omitempty"` // []apperror.FieldViolation or FieldErrors
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// WantsProblem reports whether the client asked for problem+json. The
+// envelope is offered first, so a missing Accept or */* keeps the default.
+func WantsProblem(c *gin.Context) bool {
+	return c.NegotiateFormat(MediaTypeJSON, MediaTypeProblem) == MediaTypeProblem
+}
+
+func NewProblem(c *gin.Context, status int, e *Error) Problem {
+	p := Problem{
+		Type:      ProblemTypeBase + strings.ToLower(strings.ReplaceAll(e.Code, "_", "-")),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    e.Message,
+		Instance:  c.Request.URL.RequestURI(),
+		Code:      e.Code,
+		TraceID:   observability.TraceIDFromContext(c.Request.Context()),
+		RequestID: e.RequestID,
+	}
+	if e.Details != nil {
+		e.Details.ProblemMembers(&p)
+	}
+	return p
+}
+
+// writeError renders e in the format the client negotiated, stamped with
+// the request ID so a user-reported error can be found in the logs.
+func writeError(c *gin.Context, status int, e *Error) {
+	e.RequestID = observability.RequestIDFromContext(c.Request.Context())
+	c.Writer.Header().Add("Vary", "Accept")
+	if WantsProblem(c) {
+		c.Render(status, problemRender{NewProblem(c, status, e)})
+		return
+	}
+	c.JSON(status, Response{Error: e})
+}
+
+// problemRender is render.JSON with the problem media type.
+type problemRender struct{ problem Problem }
+
+func (r problemRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return json.NewEncoder(w).Encode(r.problem)
+}
+
+func (r problemRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", MediaTypeProblem)
+}
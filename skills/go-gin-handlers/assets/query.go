@@ -0,0 +1,250 @@
+// internal/shared/query/query.go
+package query
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// List endpoints accept:
+//
+//	filter[status]=active                       equality
+//	filter[status][in]=active,pending           any of
+//	filter[start_at][gte]=2025-01-01            range (RFC 3339 or date)
+//	sort=-start_at,created_at                   "-" = descending
+//
+// Parse checks everything against a per-resource Schema, so only
+// whitelisted fields, operators and column names ever reach SQL.
+
+type Op string
+
+const (
+	OpEq  Op = "eq"
+	OpNe  Op = "ne"
+	OpIn  Op = "in"
+	OpGt  Op = "gt"
+	OpGte Op = "gte"
+	OpLt  Op = "lt"
+	OpLte Op = "lte"
+)
+
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Bool
+	Time
+)
+
+// Filter is one parsed condition. Values are typed per the field: string,
+// int64, bool or time.Time. OpIn has one or more values; others have one.
+//
+// A YYYY-MM-DD value means the whole day (UTC), so Parse rewrites range
+// operators on dates to instants: lte 2026-01-31 becomes lt 2026-02-01 and
+// gt becomes gte the next day. Op is the rewritten operator.
+type Filter struct {
+	Field  string
+	Op     Op
+	Values []any
+}
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Query is the AST for one request.
+type Query struct {
+	Filters []Filter
+	Sorts   []Sort
+}
+
+// Field whitelists one query name. Column is the SQL expression used for
+// it and never comes from the request.
+type Field struct {
+	Column   string
+	Type     Type
+	Ops      []Op     // Allowed operators; empty means eq and in
+	Enum     []string // Allowed values for String fields, if restricted
+	Sortable bool
+}
+
+type Schema struct {
+	Fields map[string]Field
+	// TieBreaker is appended to every ORDER BY so paging is stable, e.g. "id".
+	TieBreaker  string
+	DefaultSort []Sort
+	MaxFilters  int // Default 10
+	MaxValues   int // Per in filter; default 50
+}
+
+// ParseError lists every invalid parameter. Param is the query parameter,
+// e.g. "filter[status]" or "sort".
+type ParseError []ParamError
+
+type ParamError struct {
+	Param   string
+	Message string
+}
+
+func (e ParseError) Error() string {
+	msgs := make([]string, len(e))
+	for i, p := range e {
+		msgs[i] = p.Param + ": " + p.Message
+	}
+	return "invalid query: " + strings.Join(msgs, "; ")
+}
+
+var filterParam = regexp.MustCompile(`^filter\[([a-z0-9_]+)\](?:\[([a-z]+)\])?$`)
+
+func (s Schema) Parse(values url.Values) (Query, error) {
+	var (
+		q    Query
+		errs ParseError
+	)
+
+	// Sorted for deterministic SQL and error order.
+	params := slices.Sorted(maps.Keys(values))
+	for _, param := range params {
+		m := filterParam.FindStringSubmatch(param)
+		if m == nil {
+			if strings.HasPrefix(param, "filter") {
+				errs = append(errs, ParamError{param, "malformed filter; use filter[field] or filter[field][op]"})
+			}
+			continue
+		}
+		name, op := m[1], Op(m[2])
+		if op == "" {
+			op = OpEq
+		}
+		f, msg := s.parseFilter(name, op, values.Get(param))
+		if msg != "" {
+			errs = append(errs, ParamError{param, msg})
+			continue
+		}
+		q.Filters = append(q.Filters, f)
+	}
+
+	maxFilters := s.MaxFilters
+	if maxFilters == 0 {
+		maxFilters = 10
+	}
+	if len(q.Filters) > maxFilters {
+		errs = append(errs, ParamError{"filter", fmt.Sprintf("at most %d filters", maxFilters)})
+	}
+
+	if raw := values.Get("sort"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			name, desc := strings.CutPrefix(strings.TrimSpace(part), "-")
+			if f, ok := s.Fields[name]; !ok || !f.Sortable {
+				errs = append(errs, ParamError{"sort", fmt.Sprintf("cannot sort by %q", name)})
+				continue
+			}
+			q.Sorts = append(q.Sorts, Sort{Field: name, Desc: desc})
+		}
+	} else {
+		q.Sorts = s.DefaultSort
+	}
+
+	if len(errs) > 0 {
+		return Query{}, errs
+	}
+	return q, nil
+}
+
+func (s Schema) parseFilter(name string, op Op, raw string) (Filter, string) {
+	field, ok := s.Fields[name]
+	if !ok {
+		return Filter{}, "unknown filter field"
+	}
+	allowed := field.Ops
+	if len(allowed) == 0 {
+		allowed = []Op{OpEq, OpIn}
+	}
+	if !slices.Contains(allowed, op) {
+		return Filter{}, fmt.Sprintf("operator %q not allowed", op)
+	}
+
+	raws := []string{raw}
+	if op == OpIn {
+		raws = strings.Split(raw, ",")
+		maxValues := s.MaxValues
+		if maxValues == 0 {
+			maxValues = 50
+		}
+		if len(raws) > maxValues {
+			return Filter{}, fmt.Sprintf("at most %d values", maxValues)
+		}
+	}
+	if field.Type == Time && isDate(raw) {
+		return parseDateFilter(name, op, raw)
+	}
+
+	f := Filter{Field: name, Op: op}
+	for _, r := range raws {
+		v, msg := field.parseValue(strings.TrimSpace(r))
+		if msg != "" {
+			return Filter{}, msg
+		}
+		f.Values = append(f.Values, v)
+	}
+	return f, ""
+}
+
+func isDate(raw string) bool {
+	_, err := time.Parse(time.DateOnly, raw)
+	return err == nil
+}
+
+// parseDateFilter turns a whole-day bound into an instant bound. Equality
+// on a day would only match midnight, so it is rejected.
+func parseDateFilter(name string, op Op, raw string) (Filter, string) {
+	day, _ := time.Parse(time.DateOnly, raw)
+	next := day.AddDate(0, 0, 1)
+	switch op {
+	case OpGte, OpLt:
+		return Filter{Field: name, Op: op, Values: []any{day}}, ""
+	case OpGt:
+		return Filter{Field: name, Op: OpGte, Values: []any{next}}, ""
+	case OpLte:
+		return Filter{Field: name, Op: OpLt, Values: []any{next}}, ""
+	}
+	return Filter{}, "a date needs a range operator (gt, gte, lt, lte); use an RFC 3339 timestamp for " + string(op)
+}
+
+func (f Field) parseValue(raw string) (any, string) {
+	if raw == "" {
+		return nil, "value is required"
+	}
+	switch f.Type {
+	case Int:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, "must be an integer"
+		}
+		return n, ""
+	case Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, "must be true or false"
+		}
+		return b, ""
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, ""
+		}
+		return nil, "must be an RFC 3339 timestamp or YYYY-MM-DD date"
+	default:
+		if len(f.Enum) > 0 && !slices.Contains(f.Enum, raw) {
+			return nil, "must be one of: " + strings.Join(f.Enum, ", ")
+		}
+		return raw, ""
+	}
+}
@@ -0,0 +1,28 @@
+// internal/shared/server/query.go
+package server
+
+import (
+	"errors"
+
+	"api/booking/internal/shared/apperror"
+	"api/booking/internal/shared/query"
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidQuery = apperror.New(apperror.CodeValidation, "INVALID_QUERY", "Invalid filter or sort parameters")
+
+// ParseQuery reads filter[...] and sort against the resource's schema. Every
+// invalid parameter becomes a field violation named after the parameter,
+// e.g. "filter[status]".
+func ParseQuery(c *gin.Context, schema query.Schema) (query.Query, error) {
+	q, err := schema.Parse(c.Request.URL.Query())
+	var perr query.ParseError
+	if errors.As(err, &perr) {
+		out := errInvalidQuery
+		for _, p := range perr {
+			out = out.WithField(p.Param, p.Message)
+		}
+		return query.Query{}, out
+	}
+	return q, err
+}
@@ -0,0 +1,139 @@
+// internal/shared/query/memory.go
+package query
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Accessors read a field's value from an item for in-memory repositories.
+// They must return the field's type: string, int64, bool or time.Time.
+type Accessors[T any] map[string]func(T) any
+
+// ErrAccessor reports an Accessors bug: no accessor for a queried field, or
+// one returning a different type than the schema parses. It is never a
+// client error, so repositories should return it as an internal error.
+var ErrAccessor = errors.New("query accessor mismatch")
+
+// Match returns a predicate equivalent to Schema.Where. The predicate fails
+// instead of guessing when a value can't be compared.
+func Match[T any](q Query, get Accessors[T]) func(T) (bool, error) {
+	return func(item T) (bool, error) {
+		for _, f := range q.Filters {
+			v, err := get.value(f.Field, item)
+			if err != nil {
+				return false, err
+			}
+			ok := false
+			if f.Op == OpIn {
+				for _, want := range f.Values {
+					c, err := compare(f.Field, v, want)
+					if err != nil {
+						return false, err
+					}
+					if c == 0 {
+						ok = true
+						break
+					}
+				}
+			} else {
+				c, err := compare(f.Field, v, f.Values[0])
+				if err != nil {
+					return false, err
+				}
+				switch f.Op {
+				case OpEq:
+					ok = c == 0
+				case OpNe:
+					ok = c != 0
+				case OpGt:
+					ok = c > 0
+				case OpGte:
+					ok = c >= 0
+				case OpLt:
+					ok = c < 0
+				case OpLte:
+					ok = c <= 0
+				}
+			}
+			if !ok {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
+// SortItems orders items like Schema.OrderBy; tieBreaker plays the role of
+// Schema.TieBreaker. On error the order of items is unspecified.
+func SortItems[T any](items []T, q Query, get Accessors[T], tieBreaker func(T) string) error {
+	for _, s := range q.Sorts {
+		if get[s.Field] == nil {
+			return fmt.Errorf("%w: no accessor for %q", ErrAccessor, s.Field)
+		}
+	}
+
+	var sortErr error
+	slices.SortFunc(items, func(a, b T) int {
+		for _, s := range q.Sorts {
+			c, err := compare(s.Field, get[s.Field](a), get[s.Field](b))
+			if err != nil {
+				if sortErr == nil {
+					sortErr = err
+				}
+				return 0
+			}
+			if s.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		if tieBreaker == nil {
+			return 0
+		}
+		return cmp.Compare(tieBreaker(a), tieBreaker(b))
+	})
+	return sortErr
+}
+
+func (get Accessors[T]) value(field string, item T) (any, error) {
+	fn := get[field]
+	if fn == nil {
+		return nil, fmt.Errorf("%w: no accessor for %q", ErrAccessor, field)
+	}
+	return fn(item), nil
+}
+
+// compare orders two values of the same field type.
+func compare(field string, a, b any) (int, error) {
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return cmp.Compare(x, y), nil
+		}
+	case int64:
+		if y, ok := b.(int64); ok {
+			return cmp.Compare(x, y), nil
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, nil
+			case !x:
+				return -1, nil
+			}
+			return 1, nil
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q compares %T with %T", ErrAccessor, field, a, b)
+}
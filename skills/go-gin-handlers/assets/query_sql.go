@@ -0,0 +1,67 @@
+// internal/shared/query/sql.go
+package query
+
+import (
+	"strconv"
+	"strings"
+)
+
+var sqlOps = map[Op]string{
+	OpEq:  "=",
+	OpNe:  "<>",
+	OpGt:  ">",
+	OpGte: ">=",
+	OpLt:  "<",
+	OpLte: "<=",
+}
+
+// Where renders the filters as a parameterized condition for pgx, numbering
+// placeholders from $first so it can follow the query's fixed arguments.
+// It returns "" when there are no filters.
+//
+//	where, args := bookingQuery.Where(q, 2) // $1 is owner_id
+//	sql := "SELECT ... FROM bookings WHERE owner_id = $1"
+//	if where != "" {
+//		sql += " AND " + where
+//	}
+func (s Schema) Where(q Query, first int) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	next := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(first+len(args)-1)
+	}
+
+	for _, f := range q.Filters {
+		col := s.Fields[f.Field].Column
+		if f.Op == OpIn {
+			ph := make([]string, len(f.Values))
+			for i, v := range f.Values {
+				ph[i] = next(v)
+			}
+			conds = append(conds, col+" IN ("+strings.Join(ph, ", ")+")")
+			continue
+		}
+		conds = append(conds, col+" "+sqlOps[f.Op]+" "+next(f.Values[0]))
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// OrderBy renders the sorts plus the tie-breaker, without the ORDER BY
+// keyword. Columns come from the schema, never from the request.
+func (s Schema) OrderBy(q Query) string {
+	parts := make([]string, 0, len(q.Sorts)+1)
+	for _, srt := range q.Sorts {
+		col := s.Fields[srt.Field].Column
+		if srt.Desc {
+			col += " DESC"
+		}
+		parts = append(parts, col)
+	}
+	if s.TieBreaker != "" {
+		parts = append(parts, s.TieBreaker)
+	}
+	return strings.Join(parts, ", ")
+}
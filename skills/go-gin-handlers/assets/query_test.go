@@ -0,0 +1,175 @@
+// internal/shared/query/query_test.go
+package query_test
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"api/booking/internal/shared/query"
+)
+
+var schema = query.Schema{
+	Fields: map[string]query.Field{
+		"status":   {Column: "status", Enum: []string{"pending", "confirmed", "cancelled"}, Sortable: true},
+		"nights":   {Column: "nights", Type: query.Int, Ops: []query.Op{query.OpEq, query.OpNe, query.OpGt}},
+		"start_at": {Column: "start_at", Type: query.Time, Ops: []query.Op{query.OpEq, query.OpGt, query.OpGte, query.OpLt, query.OpLte}, Sortable: true},
+	},
+	TieBreaker: "id",
+}
+
+type booking struct {
+	ID      string
+	Status  string
+	Nights  int64
+	StartAt time.Time
+}
+
+var fields = query.Accessors[booking]{
+	"status":   func(b booking) any { return b.Status },
+	"nights":   func(b booking) any { return b.Nights },
+	"start_at": func(b booking) any { return b.StartAt },
+}
+
+func parse(t *testing.T, raw string) query.Query {
+	t.Helper()
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := schema.Parse(values)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", raw, err)
+	}
+	return q
+}
+
+func matching(t *testing.T, q query.Query, get query.Accessors[booking], items ...booking) []string {
+	t.Helper()
+	match := query.Match(q, get)
+	var ids []string
+	for _, b := range items {
+		ok, err := match(b)
+		if err != nil {
+			t.Fatalf("match(%s) error = %v", b.ID, err)
+		}
+		if ok {
+			ids = append(ids, b.ID)
+		}
+	}
+	return ids
+}
+
+func TestParse_DateOnlyBoundsCoverTheWholeDay(t *testing.T) {
+	day := func(h int) time.Time { return time.Date(2026, 1, 31, h, 0, 0, 0, time.UTC) }
+	items := []booking{
+		{ID: "before", StartAt: day(0).Add(-time.Second)},
+		{ID: "midnight", StartAt: day(0)},
+		{ID: "evening", StartAt: day(23)},
+		{ID: "next-day", StartAt: day(24)},
+	}
+
+	tests := []struct {
+		filter string
+		wantOp query.Op
+		want   []string
+	}{
+		{"filter[start_at][lte]=2026-01-31", query.OpLt, []string{"before", "midnight", "evening"}},
+		{"filter[start_at][lt]=2026-01-31", query.OpLt, []string{"before"}},
+		{"filter[start_at][gte]=2026-01-31", query.OpGte, []string{"midnight", "evening", "next-day"}},
+		{"filter[start_at][gt]=2026-01-31", query.OpGte, []string{"next-day"}},
+		{"filter[start_at][lte]=2026-01-31T00:00:00Z", query.OpLte, []string{"before", "midnight"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			q := parse(t, tt.filter)
+			if got := q.Filters[0].Op; got != tt.wantOp {
+				t.Errorf("op = %s, want %s", got, tt.wantOp)
+			}
+			if got := matching(t, q, fields, items...); !slices.Equal(got, tt.want) {
+				t.Errorf("matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	where, args := schema.Where(parse(t, "filter[start_at][lte]=2026-01-31"), 1)
+	if where != "start_at < $1" || !args[0].(time.Time).Equal(day(24)) {
+		t.Errorf("Where() = %q %v, want start_at < 2026-02-01", where, args)
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	tests := []struct {
+		name, raw, param string
+	}{
+		{"equality on a date", "filter[start_at]=2026-01-31", "filter[start_at]"},
+		{"too many in values", "filter[status][in]=" + strings.Repeat("pending,", 50) + "pending", "filter[status][in]"},
+		{"unknown field", "filter[owner_id]=u-1", "filter[owner_id]"},
+		{"operator not allowed", "filter[status][gt]=pending", "filter[status][gt]"},
+		{"value outside enum", "filter[status]=done", "filter[status]"},
+		{"unsortable field", "sort=nights", "sort"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.raw)
+			_, err := schema.Parse(values)
+			var perr query.ParseError
+			if !errors.As(err, &perr) || perr[0].Param != tt.param {
+				t.Errorf("Parse(%q) error = %v, want one on %s", tt.raw, err, tt.param)
+			}
+		})
+	}
+
+	values := url.Values{"filter[status][in]": {strings.Repeat("pending,", 49) + "pending"}}
+	if _, err := schema.Parse(values); err != nil {
+		t.Errorf("50 in values rejected: %v", err)
+	}
+}
+
+func TestMatch_AccessorBugsAreErrors(t *testing.T) {
+	item := booking{ID: "b-1", Status: "pending", Nights: 2}
+
+	wrongType := query.Accessors[booking]{
+		"nights": func(b booking) any { return int(b.Nights) }, // Schema parses int64
+	}
+	for _, raw := range []string{"filter[nights][ne]=3", "filter[nights][gt]=1", "filter[nights]=2"} {
+		ok, err := query.Match(parse(t, raw), wrongType)(item)
+		if !errors.Is(err, query.ErrAccessor) {
+			t.Errorf("%s: match = %v, %v; want ErrAccessor", raw, ok, err)
+		}
+	}
+
+	missing := query.Accessors[booking]{"status": fields["status"]}
+	if _, err := query.Match(parse(t, "filter[nights]=2"), missing)(item); !errors.Is(err, query.ErrAccessor) {
+		t.Errorf("missing accessor: error = %v, want ErrAccessor", err)
+	}
+	if err := query.SortItems([]booking{item, item}, parse(t, "sort=-start_at"), missing, nil); !errors.Is(err, query.ErrAccessor) {
+		t.Errorf("SortItems() with missing accessor: error = %v, want ErrAccessor", err)
+	}
+
+	if got := matching(t, parse(t, "filter[nights][ne]=3&filter[status][in]=pending,confirmed"), fields, item); !slices.Equal(got, []string{"b-1"}) {
+		t.Errorf("matched %v, want [b-1]", got)
+	}
+}
+
+func TestSortItems(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2026, 1, 1, h, 0, 0, 0, time.UTC) }
+	items := []booking{
+		{ID: "b", Status: "pending", StartAt: at(9)},
+		{ID: "a", Status: "pending", StartAt: at(9)},
+		{ID: "c", Status: "confirmed", StartAt: at(12)},
+	}
+	if err := query.SortItems(items, parse(t, "sort=-start_at,status"), fields, func(b booking) string { return b.ID }); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, b := range items {
+		ids = append(ids, b.ID)
+	}
+	if want := []string{"c", "a", "b"}; !slices.Equal(ids, want) {
+		t.Errorf("order = %v, want %v", ids, want)
+	}
+}
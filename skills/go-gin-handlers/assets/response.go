@@ -10,16 +10,27 @@ type Response struct {
 }
 
 type Error struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details any    `json:"details,omitempty"`
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Details   ErrorDetails `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty"` // Set by writeError from the request context
+}
+
+// ErrorDetails is what an error carries beyond code and message. In the
+// envelope it is marshalled as-is; in problem+json each implementation
+// places its own members. FieldErrors covers bind failures, domainDetails
+// covers the apperror catalog.
+type ErrorDetails interface {
+	ProblemMembers(p *Problem)
 }
 
 type Meta struct {
-	Page       int `json:"page,omitempty"`
-	PerPage    int `json:"per_page,omitempty"`
-	Total      int `json:"total,omitempty"`
-	TotalPages int `json:"total_pages,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page,omitempty"`
+	Total      int    `json:"total,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 func OK(c *gin.Context, status int, data any) {
@@ -30,10 +41,14 @@ func OKWithMeta(c *gin.Context, status int, data any, meta Meta) {
 	c.JSON(status, Response{Data: data, Meta: &meta})
 }
 
+// Fail and FailWithDetails write the envelope, or RFC 9457 problem+json
+// when the client sends Accept: application/problem+json.
 func Fail(c *gin.Context, status int, code string, message string) {
-	c.JSON(status, Response{Error: &Error{Code: code, Message: message}})
+	writeError(c, status, &Error{Code: code, Message: message})
 }
 
-func FailWithDetails(c *gin.Context, status int, code string, message string, details any) {
-	c.JSON(status, Response{Error: &Error{Code: code, Message: message, Details: details}})
+// For a ShouldBind* error pass ValidationDetails(c, err), which renders it
+// as localized FieldErrors instead of the raw validator string.
+func FailWithDetails(c *gin.Context, status int, code string, message string, details ErrorDetails) {
+	writeError(c, status, &Error{Code: code, Message: message, Details: details})
 }
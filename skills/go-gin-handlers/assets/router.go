@@ -5,6 +5,7 @@ import "github.com/gin-gonic/gin"
 
 func NewRouter(mode string) *gin.Engine {
 	gin.SetMode(mode)
+	RegisterValidators()
 	r := gin.New()
 
 	// Global middleware
@@ -0,0 +1,285 @@
+// internal/shared/server/validation.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError is one entry of a VALIDATION_ERROR's details. Field and
+// JSONPath use JSON names, so clients can map errors to form fields.
+type FieldError struct {
+	Field    string `json:"field"`
+	JSONPath string `json:"json_path"` // e.g. $.pet_ids[0]
+	Rule     string `json:"rule"`      // Binding tag that failed, e.g. "required"
+	Param    string `json:"param,omitempty"`
+	Message  string `json:"message"` // Localized, ready to display
+}
+
+// FieldErrors are the details of a VALIDATION_ERROR; problem+json lists
+// them under "errors".
+type FieldErrors []FieldError
+
+func (f FieldErrors) ProblemMembers(p *Problem) { p.Errors = f }
+
+const (
+	LocaleES = "es-CL"
+	LocaleEN = "en"
+)
+
+var registerOnce sync.Once
+
+// RegisterValidators makes the Gin validator report JSON field names and
+// adds the custom rules. NewRouter calls it; tests building their own
+// engine should too.
+//
+//	Amount int64  `json:"amount" binding:"required,clp"`
+//	RUT    string `json:"rut" binding:"required,rut"`
+func RegisterValidators() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return f.Name
+			}
+			return name
+		})
+		v.RegisterValidation("clp", validateCLP)
+		v.RegisterValidation("rut", validateRUT)
+	})
+}
+
+// ValidationDetails turns a ShouldBind* error into localized FieldErrors.
+// The locale comes from Accept-Language; Spanish (es-CL) is the default.
+func ValidationDetails(c *gin.Context, err error) FieldErrors {
+	return TranslateValidation(err, RequestLocale(c))
+}
+
+func TranslateValidation(err error, locale string) FieldErrors {
+	messages := catalog[locale]
+	if messages == nil {
+		messages = catalog[LocaleES]
+	}
+
+	var (
+		verrs     validator.ValidationErrors
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &verrs):
+		out := make(FieldErrors, 0, len(verrs))
+		for _, fe := range verrs {
+			out = append(out, FieldError{
+				Field:    fe.Field(),
+				JSONPath: jsonPath(fe.Namespace()),
+				Rule:     fe.Tag(),
+				Param:    fe.Param(),
+				Message:  messages.format(fe.Field(), fe.Tag(), kindOf(fe.Kind()), fe.Param()),
+			})
+		}
+		return out
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		return FieldErrors{{
+			Field:    field,
+			JSONPath: "$." + field,
+			Rule:     "type",
+			Param:    typeErr.Type.String(),
+			Message:  messages.format(field, "type", "", typeErr.Type.String()),
+		}}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return FieldErrors{{JSONPath: "$", Rule: "json", Message: messages.format("", "json", "", "")}}
+	default:
+		return FieldErrors{{JSONPath: "$", Rule: "invalid", Message: messages.format("", "invalid", "", "")}}
+	}
+}
+
+// RequestLocale picks es-CL or en from Accept-Language, in header order.
+func RequestLocale(c *gin.Context) string {
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		switch lang, _, _ := strings.Cut(strings.ToLower(tag), "-"); lang {
+		case "es":
+			return LocaleES
+		case "en":
+			return LocaleEN
+		}
+	}
+	return LocaleES
+}
+
+// jsonPath converts "CreateBookingRequest.pet_ids[0]" into "$.pet_ids[0]".
+func jsonPath(namespace string) string {
+	_, rest, found := strings.Cut(namespace, ".")
+	if !found {
+		return "$"
+	}
+	return "$." + rest
+}
+
+func kindOf(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "collection"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return ""
+}
+
+// maxCLP bounds amounts to something a booking could plausibly cost; it
+// mainly catches unit mix-ups (cents sent as pesos).
+const maxCLP = 100_000_000
+
+// validateCLP accepts whole, non-negative peso amounts. CLP has no minor
+// unit, so decimals are always a client bug.
+func validateCLP(fl validator.FieldLevel) bool {
+	f := fl.Field()
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return f.Int() >= 0 && f.Int() <= maxCLP
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return f.Uint() <= maxCLP
+	case reflect.Float32, reflect.Float64:
+		v := f.Float()
+		return v >= 0 && v <= maxCLP && v == float64(int64(v))
+	case reflect.String:
+		n, err := strconv.ParseInt(f.String(), 10, 64)
+		return err == nil && n >= 0 && n <= maxCLP
+	}
+	return false
+}
+
+func validateRUT(fl validator.FieldLevel) bool {
+	return fl.Field().Kind() == reflect.String && ValidRUT(fl.Field().String())
+}
+
+// ValidRUT checks a Chilean RUT with or without dots and dash
+// ("12.345.678-5", "12345678-5", "123456785") using the modulo-11 check
+// digit.
+func ValidRUT(rut string) bool {
+	rut = strings.ToUpper(strings.NewReplacer(".", "", "-", "", " ", "").Replace(rut))
+	if len(rut) < 2 || len(rut) > 9 {
+		return false
+	}
+	body, dv := rut[:len(rut)-1], rut[len(rut)-1]
+
+	sum, factor := 0, 2
+	for i := len(body) - 1; i >= 0; i-- {
+		d := body[i]
+		if d < '0' || d > '9' {
+			return false
+		}
+		sum += int(d-'0') * factor
+		factor++
+		if factor > 7 {
+			factor = 2
+		}
+	}
+
+	var want byte
+	switch r := 11 - sum%11; r {
+	case 11:
+		want = '0'
+	case 10:
+		want = 'K'
+	default:
+		want = byte('0' + r)
+	}
+	return dv == want
+}
+
+// messages maps "rule" or "rule.kind" to a template; {field} and {param}
+// are replaced.
+type messages map[string]string
+
+var catalog = map[string]messages{
+	LocaleES: {
+		"required":       "{field} es obligatorio",
+		"email":          "{field} debe ser un correo electrónico válido",
+		"uuid":           "{field} debe ser un identificador válido",
+		"url":            "{field} debe ser una URL válida",
+		"oneof":          "{field} debe ser uno de: {param}",
+		"datetime":       "{field} debe ser una fecha con formato {param}",
+		"min.string":     "{field} debe tener al menos {param} caracteres",
+		"min.collection": "{field} debe tener al menos {param} elementos",
+		"min":            "{field} debe ser mayor o igual a {param}",
+		"max.string":     "{field} debe tener como máximo {param} caracteres",
+		"max.collection": "{field} debe tener como máximo {param} elementos",
+		"max":            "{field} debe ser menor o igual a {param}",
+		"len.string":     "{field} debe tener {param} caracteres",
+		"len":            "{field} debe tener {param} elementos",
+		"gt":             "{field} debe ser mayor que {param}",
+		"gte":            "{field} debe ser mayor o igual a {param}",
+		"lt":             "{field} debe ser menor que {param}",
+		"lte":            "{field} debe ser menor o igual a {param}",
+		"gtfield":        "{field} debe ser posterior a {param}",
+		"clp":            "{field} debe ser un monto en pesos chilenos, sin decimales",
+		"rut":            "{field} debe ser un RUT válido",
+		"type":           "{field} tiene un tipo inválido, se esperaba {param}",
+		"json":           "El cuerpo de la solicitud no es JSON válido",
+		"invalid":        "La solicitud no es válida",
+		"default":        "{field} no es válido",
+	},
+	LocaleEN: {
+		"required":       "{field} is required",
+		"email":          "{field} must be a valid email address",
+		"uuid":           "{field} must be a valid identifier",
+		"url":            "{field} must be a valid URL",
+		"oneof":          "{field} must be one of: {param}",
+		"datetime":       "{field} must be a date in {param} format",
+		"min.string":     "{field} must be at least {param} characters",
+		"min.collection": "{field} must contain at least {param} items",
+		"min":            "{field} must be at least {param}",
+		"max.string":     "{field} must be at most {param} characters",
+		"max.collection": "{field} must contain at most {param} items",
+		"max":            "{field} must be at most {param}",
+		"len.string":     "{field} must be {param} characters long",
+		"len":            "{field} must contain {param} items",
+		"gt":             "{field} must be greater than {param}",
+		"gte":            "{field} must be greater than or equal to {param}",
+		"lt":             "{field} must be less than {param}",
+		"lte":            "{field} must be less than or equal to {param}",
+		"gtfield":        "{field} must be after {param}",
+		"clp":            "{field} must be a whole Chilean peso amount",
+		"rut":            "{field} must be a valid RUT",
+		"type":           "{field} has an invalid type, expected {param}",
+		"json":           "The request body is not valid JSON",
+		"invalid":        "The request is invalid",
+		"default":        "{field} is invalid",
+	},
+}
+
+func (m messages) format(field, rule, kind, param string) string {
+	tmpl, ok := m[rule+"."+kind]
+	if !ok {
+		if tmpl, ok = m[rule]; !ok {
+			tmpl = m["default"]
+		}
+	}
+	if rule == "oneof" {
+		param = strings.ReplaceAll(param, " ", ", ")
+	}
+	return strings.NewReplacer("{field}", field, "{param}", param).Replace(tmpl)
+}
@@ -0,0 +1,88 @@
+// internal/shared/server/validation_test.go
+package server_test
+
+import (
+	"testing"
+
+	"api/booking/internal/shared/server"
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestValidRUT(t *testing.T) {
+	tests := []struct {
+		rut  string
+		want bool
+	}{
+		{"12.345.678-5", true},
+		{"12345678-5", true},
+		{"123456785", true},
+		{"7.654.321-6", true},
+		{"10.000.013-K", true},
+		{"10000013-k", true},
+		{"1-9", true},
+		{"11.111.111-1", true},
+		{"14-0", true}, // Remainder 11 maps to 0
+
+		{"12.345.678-4", false}, // Wrong check digit
+		{"7.654.321-K", false},
+		{"10.000.013-0", false},
+		{"14-K", false},
+		{"12.3A5.678-5", false},
+		{"12.345.678-X", false},
+		{"1", false},
+		{"", false},
+		{"123.456.789-0", false}, // Body longer than 8 digits
+	}
+	for _, tt := range tests {
+		if got := server.ValidRUT(tt.rut); got != tt.want {
+			t.Errorf("ValidRUT(%q) = %v, want %v", tt.rut, got, tt.want)
+		}
+	}
+}
+
+func TestCLPRule(t *testing.T) {
+	server.RegisterValidators()
+
+	type intAmount struct {
+		Amount int64 `json:"amount" binding:"clp"`
+	}
+	type floatAmount struct {
+		Amount float64 `json:"amount" binding:"clp"`
+	}
+	type stringAmount struct {
+		Amount string `json:"amount" binding:"clp"`
+	}
+
+	tests := []struct {
+		name string
+		req  any
+		want bool
+	}{
+		{"zero", &intAmount{0}, true},
+		{"whole pesos", &intAmount{25_990}, true},
+		{"at the cap", &intAmount{100_000_000}, true},
+		{"negative", &intAmount{-1}, false},
+		{"over the cap", &intAmount{100_000_001}, false},
+		{"whole float", &floatAmount{15_000}, true},
+		{"decimals", &floatAmount{15_000.5}, false},
+		{"negative float", &floatAmount{-10}, false},
+		{"numeric string", &stringAmount{"15000"}, true},
+		{"string with decimals", &stringAmount{"15000.50"}, false},
+		{"formatted string", &stringAmount{"15.000"}, false},
+		{"empty string", &stringAmount{""}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := binding.Validator.ValidateStruct(tt.req)
+			if got := err == nil; got != tt.want {
+				t.Fatalf("valid = %v, want %v (err = %v)", got, tt.want, err)
+			}
+			if err != nil {
+				details := server.TranslateValidation(err, server.LocaleEN)
+				if len(details) != 1 || details[0].Rule != "clp" || details[0].Field != "amount" {
+					t.Errorf("details = %+v, want one clp error on amount", details)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// internal/shared/grpcauth/server.go
+package grpcauth
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"api/booking/internal/shared/apperror"
+	"api/booking/internal/shared/auth"
+	"api/booking/internal/shared/authz"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Methods maps full method names, e.g.
+// pb.CaregiverService_GetCaregiver_FullMethodName, to the policy callers
+// must satisfy. Use authz.Authenticated for "any valid token".
+type Methods map[string]authz.Policy
+
+type Config struct {
+	Validator auth.TokenValidator
+	Methods   Methods
+	// Public methods skip authentication entirely. An entry ending in "/"
+	// covers a whole service, e.g. "/grpc.health.v1.Health/".
+	Public []string
+}
+
+var (
+	errMissingToken = apperror.New(apperror.CodeUnauthorized, "TOKEN_MISSING", "missing or invalid authorization metadata")
+	errInvalidToken = apperror.New(apperror.CodeUnauthorized, "TOKEN_INVALID", "invalid token")
+	errNoPolicy     = apperror.New(apperror.CodeForbidden, "METHOD_NOT_ALLOWED", "method is not exposed")
+)
+
+// Interceptor authenticates gRPC calls with the same TokenValidator as the
+// HTTP AuthRequired middleware and enforces a policy per method. Methods
+// without a policy are denied, so a new RPC is closed until someone decides
+// who may call it.
+type Interceptor struct {
+	cfg Config
+}
+
+func NewInterceptor(cfg Config) *Interceptor {
+	return &Interceptor{cfg: cfg}
+}
+
+func (i *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := i.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (i *Interceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := i.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize returns ctx with the caller's claims and token attached, or a
+// gRPC status error.
+func (i *Interceptor) authorize(ctx context.Context, method string) (context.Context, error) {
+	if i.isPublic(method) {
+		return ctx, nil
+	}
+
+	token, ok := bearerToken(ctx)
+	if !ok {
+		return nil, errMissingToken.GRPCStatus().Err()
+	}
+	claims, err := i.cfg.Validator.Validate(ctx, token)
+	if err != nil {
+		return nil, errInvalidToken.GRPCStatus().Err()
+	}
+
+	policy, ok := i.cfg.Methods[method]
+	if !ok {
+		slog.ErrorContext(ctx, "grpc method has no access policy", "method", method)
+		return nil, errNoPolicy.GRPCStatus().Err()
+	}
+	if err := authz.Check(ctx, policy, authz.Request{Claims: claims}); err != nil {
+		appErr := apperror.From(err)
+		if appErr.Code == apperror.CodeInternal {
+			slog.ErrorContext(ctx, "authorization failed", "error", err, "method", method)
+		}
+		return nil, appErr.GRPCStatus().Err()
+	}
+
+	return auth.WithToken(auth.WithClaims(ctx, claims), token), nil
+}
+
+func (i *Interceptor) isPublic(method string) bool {
+	for _, p := range i.cfg.Public {
+		if method == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(method, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) != 1 || !strings.HasPrefix(values[0], "Bearer ") {
+		return "", false
+	}
+	token := strings.TrimPrefix(values[0], "Bearer ")
+	return token, token != ""
+}
+
+// authStream swaps in the authenticated context for streaming handlers.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context { return s.ctx }
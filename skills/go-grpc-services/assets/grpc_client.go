@@ -3,13 +3,16 @@ package infrastructure
 
 import (
 	"api/booking/internal/booking/domain"
+	"api/booking/internal/shared/apperror"
+	"api/booking/internal/shared/grpcauth"
+	"api/booking/internal/shared/observability"
 	pb "api/caregiver/proto/caregiverv1"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/status"
 )
 
 // CaregiverClient implements domain.CaregiverGateway port.
@@ -18,9 +21,21 @@ type CaregiverClient struct {
 	conn   *grpc.ClientConn
 }
 
-func NewCaregiverClient(addr string) (*CaregiverClient, error) {
+// NewCaregiverClient forwards the caller's token and request ID on every
+// call, or creds' service token when there is no caller, and records
+// rpc_client_* metrics. tlsConfig is mtls.Reloader's
+// ClientConfig; without it the connection is plaintext and needs
+// creds.Insecure.
+func NewCaregiverClient(addr string, tlsConfig *tls.Config, creds grpcauth.Credentials) (*CaregiverClient, error) {
+	transport := insecure.NewCredentials()
+	if tlsConfig != nil {
+		transport = credentials.NewTLS(tlsConfig)
+	}
 	conn, err := grpc.NewClient(addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(transport),
+		grpc.WithPerRPCCredentials(creds),
+		grpc.WithChainUnaryInterceptor(observability.UnaryClientRequestID(), observability.UnaryClientMetrics()),
+		grpc.WithChainStreamInterceptor(observability.StreamClientRequestID(), observability.StreamClientMetrics()),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to caregiver service: %w", err)
@@ -44,17 +59,17 @@ func (c *CaregiverClient) Close() error {
 	return c.conn.Close()
 }
 
+// mapGRPCToDomainError decodes the caregiver service's status back into an
+// *apperror.Error — code, reason and field violations intact — so
+// HandleDomainError renders it like a local error.
 func mapGRPCToDomainError(err error) error {
-	st, ok := status.FromError(err)
-	if !ok {
-		return err
-	}
-	switch st.Code() {
-	case codes.NotFound:
+	appErr := apperror.FromGRPC(err)
+	switch appErr.Code {
+	case apperror.CodeNotFound:
 		return domain.ErrCaregiverNotFound
-	case codes.Unavailable:
-		return fmt.Errorf("caregiver service unavailable: %w", err)
+	case apperror.CodeUnavailable:
+		return fmt.Errorf("caregiver service unavailable: %w", appErr)
 	default:
-		return fmt.Errorf("caregiver service error: %w", err)
+		return fmt.Errorf("caregiver service error: %w", appErr)
 	}
 }
@@ -0,0 +1,79 @@
+// internal/shared/grpcauth/client.go
+package grpcauth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"api/booking/internal/shared/auth"
+	"google.golang.org/grpc/credentials"
+)
+
+// TokenSource returns the token a service uses when it calls another
+// service on its own behalf (consumers, cron jobs, outbox relays).
+type TokenSource func(ctx context.Context) (string, error)
+
+// StaticToken always returns token. For local runs and tests.
+func StaticToken(token string) TokenSource {
+	return func(context.Context) (string, error) { return token, nil }
+}
+
+// CachedToken calls fetch once and reuses the token until a minute before
+// it expires. fetch can sign locally or call the auth service, e.g.
+//
+//	grpcauth.CachedToken(func(ctx context.Context) (string, time.Time, error) {
+//		return signer.Sign(auth.Claims{UserID: "svc:booking", Role: "service"})
+//	})
+func CachedToken(fetch func(ctx context.Context) (string, time.Time, error)) TokenSource {
+	var (
+		mu    sync.Mutex
+		token string
+		exp   time.Time
+	)
+	return func(ctx context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if token != "" && time.Until(exp) > time.Minute {
+			return token, nil
+		}
+		t, e, err := fetch(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get service token: %w", err)
+		}
+		token, exp = t, e
+		return token, nil
+	}
+}
+
+// Credentials attach an authorization header to every outgoing call. The
+// caller's own token (put in ctx by AuthRequired or the server interceptor)
+// is forwarded so the downstream service authorizes the end user; calls
+// made outside a request fall back to Service.
+type Credentials struct {
+	Service TokenSource // Optional; without it, calls with no caller go out unauthenticated
+	// Insecure allows sending tokens over plaintext connections. Only for
+	// local runs — tokens are bearer credentials.
+	Insecure bool
+}
+
+var _ credentials.PerRPCCredentials = Credentials{}
+
+func (c Credentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	if token, ok := auth.TokenFrom(ctx); ok {
+		return map[string]string{"authorization": "Bearer " + token}, nil
+	}
+	if c.Service == nil {
+		return nil, nil
+	}
+	token, err := c.Service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+func (c Credentials) RequireTransportSecurity() bool {
+	return !c.Insecure
+}
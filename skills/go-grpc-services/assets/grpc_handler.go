@@ -3,10 +3,12 @@ package handler
 
 import (
 	"api/caregiver/internal/caregiver/application"
+	"api/caregiver/internal/shared/apperror"
+	"api/caregiver/internal/shared/authz"
+	"api/caregiver/internal/shared/grpcauth"
 	pb "api/caregiver/proto/caregiverv1"
 	"context"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"log/slog"
 )
 
 type CaregiverGRPCHandler struct {
@@ -14,6 +16,14 @@ type CaregiverGRPCHandler struct {
 	service *application.CaregiverService
 }
 
+// Policies declares who may call each RPC. grpcauth denies methods missing
+// here, so add new RPCs to it when adding them to the proto.
+var Policies = grpcauth.Methods{
+	pb.CaregiverService_GetCaregiver_FullMethodName:    authz.Authenticated,
+	pb.CaregiverService_ListAvailable_FullMethodName:   authz.Authenticated,
+	pb.CaregiverService_VerifyCaregiver_FullMethodName: authz.Role("admin", "service"),
+}
+
 func NewCaregiverGRPCHandler(service *application.CaregiverService) *CaregiverGRPCHandler {
 	return &CaregiverGRPCHandler{service: service}
 }
@@ -21,7 +31,7 @@ func NewCaregiverGRPCHandler(service *application.CaregiverService) *CaregiverGR
 func (h *CaregiverGRPCHandler) GetCaregiver(ctx context.Context, req *pb.GetCaregiverRequest) (*pb.GetCaregiverResponse, error) {
 	caregiver, err := h.service.GetByID(ctx, req.GetCaregiverId())
 	if err != nil {
-		return nil, mapDomainErrorToGRPC(err)
+		return nil, mapDomainErrorToGRPC(ctx, err)
 	}
 
 	return &pb.GetCaregiverResponse{
@@ -29,11 +39,13 @@ func (h *CaregiverGRPCHandler) GetCaregiver(ctx context.Context, req *pb.GetCare
 	}, nil
 }
 
-func mapDomainErrorToGRPC(err error) error {
-	// Map domain errors to gRPC status codes
-	var notFound domain.NotFoundError
-	if errors.As(err, &notFound) {
-		return status.Error(codes.NotFound, err.Error())
+// mapDomainErrorToGRPC uses the same catalog as server.HandleDomainError:
+// the status carries the gRPC code plus ErrorInfo (code, reason) and
+// BadRequest field violations for the client to decode.
+func mapDomainErrorToGRPC(ctx context.Context, err error) error {
+	appErr := apperror.From(err)
+	if appErr.Code == apperror.CodeInternal {
+		slog.ErrorContext(ctx, "unhandled error", "error", err)
 	}
-	return status.Error(codes.Internal, "internal error")
+	return appErr.GRPCStatus().Err()
 }
@@ -2,17 +2,50 @@
 package server
 
 import (
+	"crypto/tls"
 	"fmt"
+	"net"
+
+	"api/booking/internal/shared/authz"
+	"api/booking/internal/shared/grpcauth"
+	"api/booking/internal/shared/observability"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
-	"net"
 )
 
-func NewGRPCServer() *grpc.Server {
-	s := grpc.NewServer(
-	// Add interceptors for logging, tracing, auth — see go-observability
-	)
-	reflection.Register(s) // Enable for dev/staging
+// PublicGRPCMethods are reachable without a token: health probes only.
+var PublicGRPCMethods = []string{
+	"/grpc.health.v1.Health/",
+}
+
+// ReflectionPolicies let any authenticated caller use reflection where
+// NewGRPCServer registers it: grpcurl -H "authorization: Bearer <token>".
+// Merge them into the service's Methods.
+var ReflectionPolicies = grpcauth.Methods{
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      authz.Authenticated,
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": authz.Authenticated,
+}
+
+// NewGRPCServer installs the request ID, RED metrics and then auth ahead
+// of opts, so every other interceptor (logging, tracing — see
+// go-observability) sees the caller, and auth failures are logged with the
+// ID and counted. With
+// tlsConfig (mtls.Reloader's ServerConfig) ServeGRPC accepts only TLS.
+// Reflection is registered outside production only, so production doesn't
+// describe its API to whoever connects.
+func NewGRPCServer(env string, authn *grpcauth.Interceptor, tlsConfig *tls.Config, opts ...grpc.ServerOption) *grpc.Server {
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(observability.UnaryServerRequestID(), observability.UnaryServerMetrics(), authn.Unary()),
+		grpc.ChainStreamInterceptor(observability.StreamServerRequestID(), observability.StreamServerMetrics(), authn.Stream()),
+	}, opts...)
+	s := grpc.NewServer(opts...)
+	if env != "production" {
+		reflection.Register(s)
+	}
 	return s
 }
 
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"api/booking/internal/booking/domain"
+	"api/booking/internal/shared/observability"
 	"github.com/nats-io/nats.go"
 )
 
@@ -27,7 +28,14 @@ func (p *NATSPublisher) Publish(ctx context.Context, topic string, event domain.
 	if err != nil {
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
-	return p.conn.Publish(topic, data)
+	msg := nats.NewMsg(topic)
+	msg.Data = data
+	// The request that caused the event travels as a header, so consumers
+	// log under the same ID without it leaking into the event schema.
+	if id := observability.RequestIDFromContext(ctx); id != "" {
+		msg.Header.Set(observability.RequestIDHeader, id)
+	}
+	return p.conn.PublishMsg(msg)
 }
 
 func (p *NATSPublisher) Close() error {
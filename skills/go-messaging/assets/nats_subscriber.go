@@ -8,6 +8,7 @@ import (
 	"log/slog"
 
 	"api/booking/internal/booking/domain"
+	"api/booking/internal/shared/observability"
 	"github.com/nats-io/nats.go"
 )
 
@@ -26,13 +27,14 @@ func NewNATSSubscriber(url string) (*NATSSubscriber, error) {
 
 func (s *NATSSubscriber) Subscribe(ctx context.Context, topic string, handler domain.EventHandler) error {
 	sub, err := s.conn.Subscribe(topic, func(msg *nats.Msg) {
+		ctx := observability.WithRequestID(ctx, observability.RequestIDOrNew(msg.Header.Get(observability.RequestIDHeader)))
 		var event domain.Event
 		if err := json.Unmarshal(msg.Data, &event); err != nil {
-			slog.Error("failed to unmarshal event", "error", err, "topic", topic)
+			slog.ErrorContext(ctx, "failed to unmarshal event", "error", err, "topic", topic)
 			return
 		}
 		if err := handler(ctx, event); err != nil {
-			slog.Error("failed to handle event", "error", err, "topic", topic, "event_id", event.ID)
+			slog.ErrorContext(ctx, "failed to handle event", "error", err, "topic", topic, "event_id", event.ID)
 			// In production: implement retry / dead letter queue
 		}
 	})
@@ -2,6 +2,8 @@ package application
 
 import (
 	"api/booking/internal/booking/domain"
+	"api/booking/internal/shared/pagination"
+	"api/booking/internal/shared/query"
 	"context"
 )
 
@@ -36,3 +38,13 @@ func (s *BookingService) CreateBooking(ctx context.Context, input CreateBookingI
 
 	return &CreateBookingOutput{ID: booking.ID.String()}, nil
 }
+
+func (s *BookingService) GetBooking(ctx context.Context, id string) (*domain.Booking, error) {
+	return s.repo.FindByID(ctx, domain.BookingID(id))
+}
+
+// ListByOwner returns one page of the owner's bookings matching q, newest
+// first by default.
+func (s *BookingService) ListByOwner(ctx context.Context, ownerID string, q query.Query, page pagination.Keyset) (pagination.KeysetPage[*domain.Booking], error) {
+	return s.repo.ListByOwner(ctx, ownerID, q, page)
+}
@@ -0,0 +1,305 @@
+// internal/shared/backup/backup.go
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"api/booking/internal/shared/storage"
+)
+
+const (
+	manifestVersion  = 1
+	defaultChunkSize = 64 << 20 // Uncompressed bytes per chunk
+)
+
+var ErrManifestInvalid = errors.New("backup: invalid manifest")
+
+// Source streams each table as COPY data. Tables are returned in an order
+// that satisfies foreign keys, so restoring in the same order never fails a
+// constraint.
+type Source interface {
+	Tables(ctx context.Context) ([]string, error)
+	CopyTo(ctx context.Context, table string, w io.Writer) error
+}
+
+// Target loads COPY data produced by the same Source format.
+type Target interface {
+	CopyFrom(ctx context.Context, table string, r io.Reader) error
+}
+
+type Manifest struct {
+	Version     int             `json:"version"`
+	Service     string          `json:"service"`
+	RunID       string          `json:"run_id"`
+	Format      string          `json:"format"` // Source COPY format, e.g. "pgcopy-binary"
+	Compression string          `json:"compression"`
+	Encrypted   bool            `json:"encrypted"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Tables      []TableManifest `json:"tables"`
+}
+
+type TableManifest struct {
+	Name   string          `json:"name"`
+	Bytes  int64           `json:"bytes"` // Uncompressed COPY bytes
+	Chunks []ChunkManifest `json:"chunks"`
+}
+
+type ChunkManifest struct {
+	Key    string `json:"key"`
+	Size   int64  `json:"size"`   // Compressed bytes
+	SHA256 string `json:"sha256"` // Of the compressed bytes
+}
+
+type Config struct {
+	Service   string
+	Bucket    string // BucketConfig.Backups
+	Format    string
+	ChunkSize int64
+	Keys      storage.KeyManager // Encrypts chunks and manifest when set
+}
+
+// Backup writes every table from src as gzip chunks, then the manifest.
+// With cfg.Keys the store is wrapped in storage.EncryptedStore; read such a
+// backup back through an EncryptedStore with the same keys.
+//
+// Keys follow backups/{service}/{date}/{run}/{service}-db-…: every run gets
+// its own run ID, so a rerun on the same day — even one that fails halfway —
+// never overwrites chunks an earlier manifest points to. Lifecycle KeepLast
+// rules still group runs as versions. The manifest is written last: a failed
+// run never leaves a manifest pointing at missing chunks.
+func Backup(ctx context.Context, store storage.ObjectStore, src Source, cfg Config) (*Manifest, string, error) {
+	if cfg.ChunkSize == 0 {
+		cfg.ChunkSize = defaultChunkSize
+	}
+	if cfg.Keys != nil {
+		store = storage.NewEncryptedStore(store, cfg.Keys)
+	}
+	now := time.Now().UTC()
+	m := &Manifest{
+		Version:     manifestVersion,
+		Service:     cfg.Service,
+		RunID:       storage.NewRunID(now),
+		Format:      cfg.Format,
+		Compression: "gzip",
+		Encrypted:   cfg.Keys != nil,
+		CreatedAt:   now,
+	}
+
+	tables, err := src.Tables(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	for _, table := range tables {
+		w := &chunkWriter{ctx: ctx, store: store, cfg: cfg, table: table, at: now, run: m.RunID}
+		if err := src.CopyTo(ctx, table, w); err != nil {
+			return nil, "", fmt.Errorf("failed to copy %s: %w", table, err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, "", err
+		}
+		m.Tables = append(m.Tables, TableManifest{Name: table, Bytes: w.total, Chunks: w.chunks})
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	key := ManifestKey(cfg.Service, now, m.RunID)
+	if err := store.Put(ctx, cfg.Bucket, key, bytes.NewReader(data), storage.PutOptions{ContentType: "application/json"}); err != nil {
+		return nil, "", fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return m, key, nil
+}
+
+// Restore loads every table listed in the manifest into dst, in manifest
+// order. Each chunk is hashed while it streams; a mismatch aborts with
+// storage.ErrChecksumMismatch. dst should run in one transaction so a
+// failed restore leaves the target untouched.
+func Restore(ctx context.Context, store storage.ObjectStore, bucket, manifestKey string, dst Target) (*Manifest, error) {
+	m, err := ReadManifest(ctx, store, bucket, manifestKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range m.Tables {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(streamChunks(ctx, store, bucket, table, pw))
+		}()
+		err := dst.CopyFrom(ctx, table.Name, pr)
+		pr.CloseWithError(err) // Unblock the reader goroutine if CopyFrom stopped early
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", table.Name, err)
+		}
+	}
+	return m, nil
+}
+
+// Verify checks every chunk against the manifest without loading anything.
+func Verify(ctx context.Context, store storage.ObjectStore, bucket, manifestKey string) (*Manifest, error) {
+	m, err := ReadManifest(ctx, store, bucket, manifestKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range m.Tables {
+		if err := streamChunks(ctx, store, bucket, table, io.Discard); err != nil {
+			return nil, fmt.Errorf("table %s: %w", table.Name, err)
+		}
+	}
+	return m, nil
+}
+
+func ReadManifest(ctx context.Context, store storage.ObjectStore, bucket, key string) (*Manifest, error) {
+	body, err := store.Get(ctx, bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	defer body.Close()
+
+	var m Manifest
+	if err := json.NewDecoder(body).Decode(&m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrManifestInvalid, err)
+	}
+	if m.Version != manifestVersion || m.Compression != "gzip" {
+		return nil, fmt.Errorf("%w: version %d, compression %q", ErrManifestInvalid, m.Version, m.Compression)
+	}
+	return &m, nil
+}
+
+// ManifestKey is backups/{service}/{date}/{run}/{service}-db-manifest.json.
+func ManifestKey(service string, at time.Time, run string) string {
+	return storage.RunKey(storage.ConcernBackups, service, at, run, service+"-db-manifest.json")
+}
+
+func chunkKey(service, table string, at time.Time, run string, seq int) string {
+	return storage.RunKey(storage.ConcernBackups, service, at, run, fmt.Sprintf("%s-db-%s-%04d.copy.gz", service, table, seq))
+}
+
+// streamChunks writes the decompressed table data to w, verifying each chunk.
+func streamChunks(ctx context.Context, store storage.ObjectStore, bucket string, table TableManifest, w io.Writer) error {
+	var total int64
+	for _, chunk := range table.Chunks {
+		n, err := readChunk(ctx, store, bucket, chunk, w)
+		if err != nil {
+			return err
+		}
+		total += n
+	}
+	if total != table.Bytes {
+		return fmt.Errorf("%w: %s has %d bytes, manifest says %d", storage.ErrChecksumMismatch, table.Name, total, table.Bytes)
+	}
+	return nil
+}
+
+func readChunk(ctx context.Context, store storage.ObjectStore, bucket string, chunk ChunkManifest, w io.Writer) (int64, error) {
+	body, err := store.Get(ctx, bucket, chunk.Key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", chunk.Key, err)
+	}
+	defer body.Close()
+
+	hasher := sha256.New()
+	zr, err := gzip.NewReader(io.TeeReader(body, hasher))
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", chunk.Key, err)
+	}
+	n, err := io.Copy(w, zr)
+	if err != nil {
+		return n, fmt.Errorf("failed to decompress %s: %w", chunk.Key, err)
+	}
+	// Drain anything after the gzip trailer so the hash covers the object.
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return n, fmt.Errorf("failed to read %s: %w", chunk.Key, err)
+	}
+	if got := hex.EncodeToString(hasher.Sum(nil)); got != chunk.SHA256 {
+		return n, fmt.Errorf("%w: %s", storage.ErrChecksumMismatch, chunk.Key)
+	}
+	return n, nil
+}
+
+// chunkWriter gzips COPY output into a buffer and uploads it every
+// cfg.ChunkSize uncompressed bytes, so memory stays bounded by one
+// compressed chunk whatever the table size.
+type chunkWriter struct {
+	ctx   context.Context
+	store storage.ObjectStore
+	cfg   Config
+	table string
+	at    time.Time
+	run   string
+
+	buf     bytes.Buffer
+	zw      *gzip.Writer
+	pending int64 // Uncompressed bytes in the current chunk
+	total   int64
+	chunks  []ChunkManifest
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if w.zw == nil {
+			w.buf.Reset()
+			w.zw = gzip.NewWriter(&w.buf)
+		}
+		n := min(int64(len(p)), w.cfg.ChunkSize-w.pending)
+		if _, err := w.zw.Write(p[:n]); err != nil {
+			return written, err
+		}
+		w.pending += n
+		w.total += n
+		written += int(n)
+		p = p[n:]
+		if w.pending == w.cfg.ChunkSize {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Close uploads the last partial chunk. Empty tables still get one chunk so
+// the manifest lists every table.
+func (w *chunkWriter) Close() error {
+	if w.zw == nil && len(w.chunks) > 0 {
+		return nil
+	}
+	if w.zw == nil {
+		w.buf.Reset()
+		w.zw = gzip.NewWriter(&w.buf)
+	}
+	return w.flush()
+}
+
+func (w *chunkWriter) flush() error {
+	if err := w.zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", w.table, err)
+	}
+	w.zw, w.pending = nil, 0
+
+	sum := sha256.Sum256(w.buf.Bytes())
+	chunk := ChunkManifest{
+		Key:    chunkKey(w.cfg.Service, w.table, w.at, w.run, len(w.chunks)+1),
+		Size:   int64(w.buf.Len()),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+	if err := w.store.Put(w.ctx, w.cfg.Bucket, chunk.Key, &w.buf, storage.PutOptions{
+		ContentType: "application/gzip",
+		Metadata:    map[string]string{"sha256": chunk.SHA256},
+	}); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", chunk.Key, err)
+	}
+	w.chunks = append(w.chunks, chunk)
+	return nil
+}
@@ -0,0 +1,163 @@
+// cmd/backup/main.go
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"api/booking/internal/shared/backup"
+	"api/booking/internal/shared/config"
+	"api/booking/internal/shared/storage"
+)
+
+// Usage:
+//
+//	backup create  -service booking
+//	backup verify  -manifest backups/booking/2025-01-15/run-093012-3f2a9c1e/booking-db-manifest.json
+//	backup restore -manifest … -target-db booking_restore
+//
+// Database and storage settings come from the same environment as the
+// service. Set STORAGE_KEY_FILE to encrypt (and to read encrypted backups).
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: backup create|verify|restore [flags]")
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx, os.Args[1], os.Args[2:]); err != nil {
+		slog.Error("backup command failed", "command", os.Args[1], "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, command string, args []string) error {
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	service := fs.String("service", "booking", "service name used in backup keys")
+	schema := fs.String("schema", "public", "Postgres schema to back up or restore")
+	exclude := fs.String("exclude", "schema_migrations", "comma-separated tables to skip")
+	chunkSize := fs.Int64("chunk-size", 64<<20, "uncompressed bytes per chunk")
+	manifest := fs.String("manifest", "", "manifest key (verify, restore)")
+	targetDB := fs.String("target-db", "", "database to restore into (default: DB_NAME)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	store, err := storage.NewObjectStore(ctx, cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to create object store: %w", err)
+	}
+	keys, err := loadKeys(cfg.Storage)
+	if err != nil {
+		return err
+	}
+	// verify and restore read through the same keys create encrypted with.
+	readStore := store
+	if keys != nil {
+		readStore = storage.NewEncryptedStore(store, keys)
+	}
+	bucket := cfg.Storage.Buckets.Backups
+
+	switch command {
+	case "create":
+		pool, err := config.NewPostgresPool(ctx, cfg.Database)
+		if err != nil {
+			return err
+		}
+		defer pool.Close()
+
+		src, err := backup.BeginPostgresSource(ctx, pool, *schema, strings.Split(*exclude, ","))
+		if err != nil {
+			return err
+		}
+		defer src.Close(context.WithoutCancel(ctx))
+
+		started := time.Now()
+		m, key, err := backup.Backup(ctx, store, src, backup.Config{
+			Service:   *service,
+			Bucket:    bucket,
+			Format:    backup.FormatPgCopyBinary,
+			ChunkSize: *chunkSize,
+			Keys:      keys,
+		})
+		if err != nil {
+			return err
+		}
+		slog.Info("backup completed",
+			"manifest", key,
+			"tables", len(m.Tables),
+			"encrypted", m.Encrypted,
+			"duration", time.Since(started),
+		)
+		return nil
+
+	case "verify":
+		if *manifest == "" {
+			return errors.New("-manifest is required")
+		}
+		m, err := backup.Verify(ctx, readStore, bucket, *manifest)
+		if err != nil {
+			return err
+		}
+		slog.Info("backup verified", "manifest", *manifest, "tables", len(m.Tables), "created_at", m.CreatedAt)
+		return nil
+
+	case "restore":
+		if *manifest == "" {
+			return errors.New("-manifest is required")
+		}
+		dbCfg := cfg.Database
+		if *targetDB != "" {
+			dbCfg.DBName = *targetDB
+		}
+		pool, err := config.NewPostgresPool(ctx, dbCfg)
+		if err != nil {
+			return err
+		}
+		defer pool.Close()
+
+		target, err := backup.BeginPostgresTarget(ctx, pool, *schema)
+		if err != nil {
+			return err
+		}
+		defer target.Rollback(context.WithoutCancel(ctx)) // No-op after Commit
+
+		m, err := backup.Restore(ctx, readStore, bucket, *manifest, target)
+		if err != nil {
+			return err
+		}
+		if err := target.Commit(ctx); err != nil {
+			return fmt.Errorf("failed to commit restore: %w", err)
+		}
+		slog.Info("restore completed", "manifest", *manifest, "database", dbCfg.DBName, "tables", len(m.Tables))
+		return nil
+	}
+	return fmt.Errorf("unknown command %q", command)
+}
+
+// loadKeys returns nil when STORAGE_KEY_FILE is unset: backups are plain.
+func loadKeys(cfg config.StorageConfig) (storage.KeyManager, error) {
+	if cfg.KeyFile == "" {
+		return nil, nil
+	}
+	keys, err := storage.NewLocalKeyManager(cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load storage keys: %w", err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,126 @@
+// internal/shared/backup/postgres.go
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// FormatPgCopyBinary is COPY … (FORMAT binary): compact and type-exact, but
+// only loadable into a schema with the same column types.
+const FormatPgCopyBinary = "pgcopy-binary"
+
+// tablesInDependencyOrder lists base tables in schema, parents before the
+// tables that reference them. Self-references are ignored; tables in a
+// foreign-key cycle cannot be ordered and need deferrable constraints.
+const tablesInDependencyOrder = `
+WITH RECURSIVE tables AS (
+    SELECT c.oid, c.relname
+    FROM pg_class c
+    JOIN pg_namespace n ON n.oid = c.relnamespace
+    WHERE n.nspname = $1 AND c.relkind = 'r' AND c.relname <> ALL($2::text[])
+),
+edges AS (
+    SELECT DISTINCT con.conrelid AS child, con.confrelid AS parent
+    FROM pg_constraint con
+    WHERE con.contype = 'f'
+      AND con.conrelid <> con.confrelid
+      AND con.conrelid IN (SELECT oid FROM tables)
+      AND con.confrelid IN (SELECT oid FROM tables)
+),
+depth AS (
+    SELECT oid, 0 AS level FROM tables
+    UNION ALL
+    SELECT e.child, d.level + 1
+    FROM edges e
+    JOIN depth d ON d.oid = e.parent
+    WHERE d.level < (SELECT COUNT(*) FROM tables) -- Stops on FK cycles
+)
+SELECT t.relname
+FROM tables t
+JOIN depth d ON d.oid = t.oid
+GROUP BY t.relname
+ORDER BY MAX(d.level), t.relname`
+
+// PostgresSource reads all tables from one REPEATABLE READ snapshot, so the
+// backup is consistent across tables while the service keeps writing.
+type PostgresSource struct {
+	tx      pgx.Tx
+	schema  string
+	exclude []string
+}
+
+// BeginPostgresSource opens the snapshot. Always Close it. exclude skips
+// tables such as schema_migrations, which the restore target already has.
+func BeginPostgresSource(ctx context.Context, pool *pgxpool.Pool, schema string, exclude []string) (*PostgresSource, error) {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin snapshot: %w", err)
+	}
+	if exclude == nil {
+		exclude = []string{}
+	}
+	return &PostgresSource{tx: tx, schema: schema, exclude: exclude}, nil
+}
+
+func (s *PostgresSource) Tables(ctx context.Context) ([]string, error) {
+	rows, err := s.tx.Query(ctx, tablesInDependencyOrder, s.schema, s.exclude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func (s *PostgresSource) CopyTo(ctx context.Context, table string, w io.Writer) error {
+	sql := fmt.Sprintf("COPY %s TO STDOUT (FORMAT binary)", pgx.Identifier{s.schema, table}.Sanitize())
+	_, err := s.tx.Conn().PgConn().CopyTo(ctx, w, sql)
+	return err
+}
+
+func (s *PostgresSource) Close(ctx context.Context) error {
+	return s.tx.Rollback(ctx) // Read-only; nothing to commit
+}
+
+// PostgresTarget loads all tables in one transaction: either the whole
+// backup is restored or nothing is.
+type PostgresTarget struct {
+	tx     pgx.Tx
+	schema string
+}
+
+// BeginPostgresTarget requires the schema to exist (run migrations first)
+// and every restored table to be empty.
+func BeginPostgresTarget(ctx context.Context, pool *pgxpool.Pool, schema string) (*PostgresTarget, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin restore: %w", err)
+	}
+	return &PostgresTarget{tx: tx, schema: schema}, nil
+}
+
+func (t *PostgresTarget) CopyFrom(ctx context.Context, table string, r io.Reader) error {
+	ident := pgx.Identifier{t.schema, table}.Sanitize()
+
+	var nonEmpty bool
+	if err := t.tx.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s)", ident)).Scan(&nonEmpty); err != nil {
+		return fmt.Errorf("failed to check %s: %w", table, err)
+	}
+	if nonEmpty {
+		return fmt.Errorf("target table %s is not empty", table)
+	}
+
+	_, err := t.tx.Conn().PgConn().CopyFrom(ctx, r, fmt.Sprintf("COPY %s FROM STDIN (FORMAT binary)", ident))
+	return err
+}
+
+func (t *PostgresTarget) Commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+func (t *PostgresTarget) Rollback(ctx context.Context) error {
+	return t.tx.Rollback(ctx)
+}
@@ -0,0 +1,245 @@
+// internal/shared/backup/backup_test.go
+package backup_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"api/booking/internal/shared/backup"
+	"api/booking/internal/shared/storage"
+)
+
+const bucket = "bastet-backups"
+
+// fakeSource serves fixed COPY payloads in declaration order.
+type fakeSource struct {
+	order  []string
+	tables map[string][]byte
+}
+
+func (s *fakeSource) Tables(ctx context.Context) ([]string, error) { return s.order, nil }
+
+func (s *fakeSource) CopyTo(ctx context.Context, table string, w io.Writer) error {
+	// Small writes, like a COPY stream, so chunk boundaries fall mid-write.
+	data := s.tables[table]
+	for len(data) > 0 {
+		n := min(len(data), 700)
+		if _, err := w.Write(data[:n]); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+type fakeTarget struct {
+	order  []string
+	tables map[string][]byte
+}
+
+func (t *fakeTarget) CopyFrom(ctx context.Context, table string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if t.tables == nil {
+		t.tables = make(map[string][]byte)
+	}
+	t.order = append(t.order, table)
+	t.tables[table] = data
+	return nil
+}
+
+func newSource(t *testing.T) *fakeSource {
+	t.Helper()
+	users := make([]byte, 10_000)
+	if _, err := rand.Read(users); err != nil {
+		t.Fatal(err)
+	}
+	return &fakeSource{
+		order: []string{"users", "bookings", "audit_logs"},
+		tables: map[string][]byte{
+			"users":      users,
+			"bookings":   bytes.Repeat([]byte("booking-row;"), 500),
+			"audit_logs": nil,
+		},
+	}
+}
+
+func TestBackupRestore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewInMemoryStore()
+	src := newSource(t)
+
+	m, key, err := backup.Backup(ctx, store, src, backup.Config{
+		Service:   "booking",
+		Bucket:    bucket,
+		Format:    backup.FormatPgCopyBinary,
+		ChunkSize: 4096,
+	})
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	if got := len(m.Tables[0].Chunks); got != 3 {
+		t.Errorf("users chunks = %d, want 3", got)
+	}
+	if got := len(m.Tables[2].Chunks); got != 1 {
+		t.Errorf("empty table chunks = %d, want 1", got)
+	}
+	if m.Encrypted {
+		t.Error("Encrypted = true for a plain store")
+	}
+
+	target := &fakeTarget{}
+	if _, err := backup.Restore(ctx, store, bucket, key, target); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	if fmt.Sprint(target.order) != fmt.Sprint(src.order) {
+		t.Errorf("restore order = %v, want %v", target.order, src.order)
+	}
+	for table, want := range src.tables {
+		if !bytes.Equal(target.tables[table], want) {
+			t.Errorf("table %s: restored %d bytes, want %d", table, len(target.tables[table]), len(want))
+		}
+	}
+}
+
+func TestRestore_DetectsCorruptedChunk(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewInMemoryStore()
+
+	m, key, err := backup.Backup(ctx, store, newSource(t), backup.Config{Service: "booking", Bucket: bucket, ChunkSize: 4096})
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	// Replace a chunk with valid gzip of different content.
+	other := &fakeSource{order: []string{"users"}, tables: map[string][]byte{"users": []byte("tampered")}}
+	otherStore := storage.NewInMemoryStore()
+	om, _, err := backup.Backup(ctx, otherStore, other, backup.Config{Service: "other", Bucket: bucket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := otherStore.Get(ctx, bucket, om.Tables[0].Chunks[0].Key)
+	if err := store.Put(ctx, bucket, m.Tables[1].Chunks[0].Key, body, storage.PutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := backup.Verify(ctx, store, bucket, key); !errors.Is(err, storage.ErrChecksumMismatch) {
+		t.Errorf("Verify() error = %v, want ErrChecksumMismatch", err)
+	}
+	if _, err := backup.Restore(ctx, store, bucket, key, &fakeTarget{}); !errors.Is(err, storage.ErrChecksumMismatch) {
+		t.Errorf("Restore() error = %v, want ErrChecksumMismatch", err)
+	}
+}
+
+// failingSource fails partway through its last table, like a dropped
+// connection during COPY.
+type failingSource struct{ *fakeSource }
+
+func (s failingSource) CopyTo(ctx context.Context, table string, w io.Writer) error {
+	if table == s.order[len(s.order)-1] {
+		return errors.New("connection reset")
+	}
+	return s.fakeSource.CopyTo(ctx, table, w)
+}
+
+func TestBackup_FailedRerunKeepsEarlierBackup(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewInMemoryStore()
+	cfg := backup.Config{Service: "booking", Bucket: bucket, ChunkSize: 4096}
+
+	first, key, err := backup.Backup(ctx, store, newSource(t), cfg)
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	// Same day, different data, fails after writing some chunks.
+	if _, _, err := backup.Backup(ctx, store, failingSource{newSource(t)}, cfg); err == nil {
+		t.Fatal("Backup() succeeded with a failing source")
+	}
+
+	objects, err := store.List(ctx, bucket, "backups/booking/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// First run: 6 chunks + manifest. The rerun adds its users and bookings
+	// chunks under its own run ID, and no manifest.
+	if len(objects) != 7+5 {
+		t.Errorf("objects = %d, want 12", len(objects))
+	}
+	if _, err := backup.Verify(ctx, store, bucket, key); err != nil {
+		t.Errorf("Verify(first) error = %v — rerun overwrote its chunks", err)
+	}
+	if _, err := backup.Restore(ctx, store, bucket, key, &fakeTarget{}); err != nil {
+		t.Errorf("Restore(first) error = %v", err)
+	}
+	if first.RunID == "" {
+		t.Error("manifest has no run ID")
+	}
+}
+
+func TestBackup_Encrypted(t *testing.T) {
+	ctx := context.Background()
+	inner := storage.NewInMemoryStore()
+	keys := newKeyManager(t)
+	src := newSource(t)
+
+	m, key, err := backup.Backup(ctx, inner, src, backup.Config{Service: "booking", Bucket: bucket, ChunkSize: 4096, Keys: keys})
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if !m.Encrypted {
+		t.Error("Encrypted = false with Keys set")
+	}
+
+	raw, err := inner.Get(ctx, bucket, m.Tables[1].Chunks[0].Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := make([]byte, 2)
+	io.ReadFull(raw, head)
+	if bytes.Equal(head, []byte{0x1f, 0x8b}) {
+		t.Error("chunk is stored as plain gzip")
+	}
+
+	if _, err := backup.ReadManifest(ctx, inner, bucket, key); err == nil {
+		t.Error("manifest readable without the key")
+	}
+
+	target := &fakeTarget{}
+	if _, err := backup.Restore(ctx, storage.NewEncryptedStore(inner, keys), bucket, key, target); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if !bytes.Equal(target.tables["bookings"], src.tables["bookings"]) {
+		t.Error("bookings not restored")
+	}
+}
+
+func newKeyManager(t *testing.T) *storage.LocalKeyManager {
+	t.Helper()
+	kek := make([]byte, 32)
+	if _, err := rand.Read(kek); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "keys.json")
+	content := fmt.Sprintf(`{"current":"k1","keys":{"k1":%q}}`, base64.StdEncoding.EncodeToString(kek))
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := storage.NewLocalKeyManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
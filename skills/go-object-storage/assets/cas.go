@@ -0,0 +1,169 @@
+// internal/shared/storage/cas.go
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+)
+
+var (
+	ErrRefNotFound = errors.New("storage: content reference not found")
+	// ErrBlobMissing is returned by ContentIndex.Link when the collector
+	// deleted the blob between the caller's upload and the link.
+	ErrBlobMissing = errors.New("storage: blob missing from the object store")
+)
+
+// BlobRef links a logical key to the blob holding its content.
+type BlobRef struct {
+	Namespace   string
+	Key         string
+	Digest      string // Hex SHA-256
+	Size        int64
+	ContentType string
+}
+
+// ContentIndex is the reference-counted key → digest index. The Postgres
+// implementation lives in casindex; InMemoryContentIndex is for tests.
+type ContentIndex interface {
+	// Link points ref.Key at ref.Digest and increments the blob's refcount.
+	// exists runs while the blob is locked against garbage collection; if it
+	// reports the blob missing, Link returns ErrBlobMissing and links nothing.
+	// Keep it to a quick existence check: the lock is held while it runs.
+	Link(ctx context.Context, ref BlobRef, exists func(ctx context.Context) (bool, error)) error
+	// Unlink removes the key and decrements the blob's refcount.
+	Unlink(ctx context.Context, namespace, key string) error
+	Resolve(ctx context.Context, namespace, key string) (*BlobRef, error)
+	// Collect locks up to limit blobs unreferenced since before cutoff, calls
+	// remove for each, and deletes their index rows if remove succeeds.
+	Collect(ctx context.Context, cutoff time.Time, limit int, remove func(ctx context.Context, digest string) error) (int, error)
+}
+
+// ContentStore deduplicates objects by SHA-256. Callers Put/Get by logical
+// key; identical content is stored once under blobs/sha256/ab/cd/abcd….
+type ContentStore struct {
+	store     ObjectStore
+	index     ContentIndex
+	bucket    string
+	namespace string // Logical key space, e.g. "uploads" or "backups"
+	tempDir   string
+}
+
+func NewContentStore(store ObjectStore, index ContentIndex, bucket, namespace string) *ContentStore {
+	return &ContentStore{store: store, index: index, bucket: bucket, namespace: namespace, tempDir: os.TempDir()}
+}
+
+// Put spools reader to a temp file while hashing it, uploads the blob if the
+// store doesn't already hold it, then links the key. The upload happens
+// before the index locks anything; if the collector deletes the blob before
+// the link, the upload is repeated.
+func (s *ContentStore) Put(ctx context.Context, key string, reader io.Reader, opts PutOptions) (*BlobRef, error) {
+	spool, err := os.CreateTemp(s.tempDir, "cas-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spool file: %w", err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(spool, hasher), reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool content: %w", err)
+	}
+
+	ref := BlobRef{
+		Namespace:   s.namespace,
+		Key:         key,
+		Digest:      hex.EncodeToString(hasher.Sum(nil)),
+		Size:        size,
+		ContentType: opts.ContentType,
+	}
+	blobKey := BlobKey(ref.Digest)
+	exists := func(ctx context.Context) (bool, error) {
+		return s.store.Exists(ctx, s.bucket, blobKey)
+	}
+
+	const attempts = 3
+	for range attempts {
+		if err := s.upload(ctx, spool, blobKey, ref, exists); err != nil {
+			return nil, err
+		}
+		err = s.index.Link(ctx, ref, exists)
+		if !errors.Is(err, ErrBlobMissing) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to link %s: %w", key, err)
+	}
+	return &ref, nil
+}
+
+// upload stores the spooled content unless the blob already exists.
+// Re-uploading identical bytes is always safe.
+func (s *ContentStore) upload(ctx context.Context, spool *os.File, blobKey string, ref BlobRef, exists func(ctx context.Context) (bool, error)) error {
+	ok, err := exists(ctx)
+	if err != nil || ok {
+		return err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind spool file: %w", err)
+	}
+	if err := s.store.Put(ctx, s.bucket, blobKey, spool, PutOptions{
+		ContentType: ref.ContentType,
+		Metadata:    map[string]string{"sha256": ref.Digest},
+	}); err != nil {
+		return fmt.Errorf("failed to upload blob %s: %w", ref.Digest, err)
+	}
+	return nil
+}
+
+func (s *ContentStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	ref, err := s.index.Resolve(ctx, s.namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.Get(ctx, s.bucket, BlobKey(ref.Digest))
+}
+
+func (s *ContentStore) Stat(ctx context.Context, key string) (*BlobRef, error) {
+	return s.index.Resolve(ctx, s.namespace, key)
+}
+
+// Delete removes the logical key. The blob stays until CollectGarbage finds
+// it unreferenced for longer than the grace period.
+func (s *ContentStore) Delete(ctx context.Context, key string) error {
+	return s.index.Unlink(ctx, s.namespace, key)
+}
+
+// CollectGarbage deletes blobs unreferenced for longer than grace, in batches.
+// Safe to run concurrently with writers and with other collectors: each blob
+// is locked (SKIP LOCKED) while its object and row are removed.
+func (s *ContentStore) CollectGarbage(ctx context.Context, grace time.Duration) (int, error) {
+	const batch = 100
+	cutoff := time.Now().Add(-grace)
+	total := 0
+	for {
+		n, err := s.index.Collect(ctx, cutoff, batch, func(ctx context.Context, digest string) error {
+			return s.store.Delete(ctx, s.bucket, BlobKey(digest))
+		})
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("garbage collection failed: %w", err)
+		}
+		if n < batch {
+			return total, nil
+		}
+	}
+}
+
+// BlobKey fans blobs out over two directory levels to keep listings small.
+func BlobKey(digest string) string {
+	return path.Join("blobs", "sha256", digest[0:2], digest[2:4], digest)
+}
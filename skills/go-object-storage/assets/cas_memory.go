@@ -0,0 +1,125 @@
+// internal/shared/storage/casindex/memory.go
+package casindex
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"api/booking/internal/shared/storage"
+)
+
+type memoryBlob struct {
+	size         int64
+	contentType  string
+	references   int
+	unreferenced time.Time
+}
+
+// InMemoryContentIndex implements storage.ContentIndex for tests. A single
+// mutex stands in for the row locks of the Postgres index.
+type InMemoryContentIndex struct {
+	mu    sync.Mutex
+	blobs map[string]*memoryBlob // digest → blob
+	refs  map[string]string      // namespace/key → digest
+	now   func() time.Time
+}
+
+func NewInMemoryContentIndex() *InMemoryContentIndex {
+	return &InMemoryContentIndex{
+		blobs: make(map[string]*memoryBlob),
+		refs:  make(map[string]string),
+		now:   time.Now,
+	}
+}
+
+func (i *InMemoryContentIndex) Link(ctx context.Context, ref storage.BlobRef, exists func(ctx context.Context) (bool, error)) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	ok, err := exists(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return storage.ErrBlobMissing
+	}
+
+	blob, ok := i.blobs[ref.Digest]
+	if !ok {
+		blob = &memoryBlob{size: ref.Size, contentType: ref.ContentType}
+		i.blobs[ref.Digest] = blob
+	}
+
+	refKey := ref.Namespace + "/" + ref.Key
+	if current, ok := i.refs[refKey]; ok {
+		if current == ref.Digest {
+			return nil
+		}
+		i.release(current)
+	}
+	i.refs[refKey] = ref.Digest
+	blob.references++
+	return nil
+}
+
+func (i *InMemoryContentIndex) Unlink(ctx context.Context, namespace, key string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	refKey := namespace + "/" + key
+	digest, ok := i.refs[refKey]
+	if !ok {
+		return storage.ErrRefNotFound
+	}
+	delete(i.refs, refKey)
+	i.release(digest)
+	return nil
+}
+
+func (i *InMemoryContentIndex) Resolve(ctx context.Context, namespace, key string) (*storage.BlobRef, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	digest, ok := i.refs[namespace+"/"+key]
+	if !ok {
+		return nil, storage.ErrRefNotFound
+	}
+	blob := i.blobs[digest]
+	return &storage.BlobRef{
+		Namespace:   namespace,
+		Key:         key,
+		Digest:      digest,
+		Size:        blob.size,
+		ContentType: blob.contentType,
+	}, nil
+}
+
+func (i *InMemoryContentIndex) Collect(ctx context.Context, cutoff time.Time, limit int, remove func(ctx context.Context, digest string) error) (int, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	removed := 0
+	for digest, blob := range i.blobs {
+		if removed == limit {
+			break
+		}
+		if blob.references > 0 || !blob.unreferenced.Before(cutoff) {
+			continue
+		}
+		if err := remove(ctx, digest); err != nil {
+			return removed, err
+		}
+		delete(i.blobs, digest)
+		removed++
+	}
+	return removed, nil
+}
+
+func (i *InMemoryContentIndex) release(digest string) {
+	blob := i.blobs[digest]
+	blob.references--
+	if blob.references == 0 {
+		blob.unreferenced = i.now()
+	}
+}
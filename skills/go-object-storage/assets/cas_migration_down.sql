@@ -0,0 +1,3 @@
+-- migrations/000010_create_content_blobs.down.sql
+DROP TABLE IF EXISTS content_references;
+DROP TABLE IF EXISTS content_blobs;
@@ -0,0 +1,28 @@
+-- migrations/000010_create_content_blobs.up.sql
+CREATE TABLE content_blobs (
+    id              UUID NOT NULL,
+    digest          CHAR(64) NOT NULL,
+    size_bytes      BIGINT NOT NULL,
+    content_type    VARCHAR(255) NOT NULL DEFAULT '',
+    reference_count INTEGER NOT NULL DEFAULT 0,
+    unreferenced_at TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_content_blobs PRIMARY KEY (id),
+    CONSTRAINT uq_content_blobs_digest UNIQUE (digest),
+    CONSTRAINT ck_content_blobs_reference_count CHECK (reference_count >= 0)
+);
+
+CREATE TABLE content_references (
+    id              UUID NOT NULL,
+    namespace       VARCHAR(50) NOT NULL,
+    object_key      VARCHAR(1024) NOT NULL,
+    content_blob_id UUID NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_content_references PRIMARY KEY (id),
+    CONSTRAINT uq_content_references_namespace_object_key UNIQUE (namespace, object_key),
+    CONSTRAINT fk_content_references_content_blobs FOREIGN KEY (content_blob_id) REFERENCES content_blobs(id)
+);
+
+CREATE INDEX idx_content_references_content_blob_id ON content_references(content_blob_id);
+CREATE INDEX idx_content_blobs_unreferenced_at ON content_blobs(unreferenced_at) WHERE reference_count = 0;
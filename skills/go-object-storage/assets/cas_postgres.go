@@ -0,0 +1,166 @@
+// internal/shared/storage/casindex/postgres.go
+package casindex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"api/booking/internal/shared/storage"
+	"api/booking/internal/shared/storage/casindex/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresContentIndex implements storage.ContentIndex. Row locks on
+// content_blobs serialize writers and the garbage collector per digest.
+type PostgresContentIndex struct {
+	pool *pgxpool.Pool
+	q    *db.Queries
+}
+
+func NewPostgresContentIndex(pool *pgxpool.Pool) *PostgresContentIndex {
+	return &PostgresContentIndex{pool: pool, q: db.New(pool)}
+}
+
+func (i *PostgresContentIndex) Link(ctx context.Context, ref storage.BlobRef, exists func(ctx context.Context) (bool, error)) error {
+	return i.withTx(ctx, func(q *db.Queries) error {
+		blob, err := lockOrInsertBlob(ctx, q, ref)
+		if err != nil {
+			return err
+		}
+
+		// The caller uploaded before calling Link; re-check under the lock in
+		// case the collector deleted the blob in between.
+		ok, err := exists(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check blob: %w", err)
+		}
+		if !ok {
+			return storage.ErrBlobMissing
+		}
+
+		current, err := q.GetReferenceForUpdate(ctx, db.GetReferenceForUpdateParams{Namespace: ref.Namespace, ObjectKey: ref.Key})
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+		case err != nil:
+			return fmt.Errorf("failed to read reference: %w", err)
+		case current.ContentBlobID == blob.ID:
+			return nil // Same content re-uploaded under the same key
+		default:
+			if err := q.DecrementBlobReferences(ctx, current.ContentBlobID); err != nil {
+				return fmt.Errorf("failed to release previous blob: %w", err)
+			}
+		}
+
+		if err := q.UpsertReference(ctx, db.UpsertReferenceParams{
+			ID:            uuid.NewString(),
+			Namespace:     ref.Namespace,
+			ObjectKey:     ref.Key,
+			ContentBlobID: blob.ID,
+		}); err != nil {
+			return fmt.Errorf("failed to save reference: %w", err)
+		}
+		return q.IncrementBlobReferences(ctx, blob.ID)
+	})
+}
+
+func (i *PostgresContentIndex) Unlink(ctx context.Context, namespace, key string) error {
+	return i.withTx(ctx, func(q *db.Queries) error {
+		blobID, err := q.DeleteReference(ctx, db.DeleteReferenceParams{Namespace: namespace, ObjectKey: key})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return storage.ErrRefNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete reference: %w", err)
+		}
+		return q.DecrementBlobReferences(ctx, blobID)
+	})
+}
+
+func (i *PostgresContentIndex) Resolve(ctx context.Context, namespace, key string) (*storage.BlobRef, error) {
+	row, err := i.q.GetReference(ctx, db.GetReferenceParams{Namespace: namespace, ObjectKey: key})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrRefNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve reference: %w", err)
+	}
+	return &storage.BlobRef{
+		Namespace:   row.Namespace,
+		Key:         row.ObjectKey,
+		Digest:      row.Digest,
+		Size:        row.SizeBytes,
+		ContentType: row.ContentType,
+	}, nil
+}
+
+func (i *PostgresContentIndex) Collect(ctx context.Context, cutoff time.Time, limit int, remove func(ctx context.Context, digest string) error) (int, error) {
+	removed := 0
+	err := i.withTx(ctx, func(q *db.Queries) error {
+		blobs, err := q.LockCollectableBlobs(ctx, db.LockCollectableBlobsParams{
+			UnreferencedAt: &cutoff,
+			Limit:          int32(limit),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to select collectable blobs: %w", err)
+		}
+		for _, blob := range blobs {
+			// Row first, object second: if the object delete fails the
+			// transaction rolls back and the blob is retried next run.
+			// Writers that find the row but not the object get
+			// ErrBlobMissing and upload again.
+			if err := q.DeleteBlob(ctx, blob.ID); err != nil {
+				return fmt.Errorf("failed to delete blob row: %w", err)
+			}
+			if err := remove(ctx, blob.Digest); err != nil {
+				return fmt.Errorf("failed to delete blob %s: %w", blob.Digest, err)
+			}
+			removed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// lockOrInsertBlob returns the locked blob row, creating it if needed. If the
+// collector deletes the row while we wait for its lock, the lock query finds
+// nothing; the row is then inserted again in a second attempt.
+func lockOrInsertBlob(ctx context.Context, q *db.Queries, ref storage.BlobRef) (db.ContentBlob, error) {
+	for attempt := 0; ; attempt++ {
+		if err := q.InsertBlobIfAbsent(ctx, db.InsertBlobIfAbsentParams{
+			ID:          uuid.NewString(),
+			Digest:      ref.Digest,
+			SizeBytes:   ref.Size,
+			ContentType: ref.ContentType,
+		}); err != nil {
+			return db.ContentBlob{}, fmt.Errorf("failed to insert blob: %w", err)
+		}
+		blob, err := q.LockBlobByDigest(ctx, ref.Digest)
+		if errors.Is(err, pgx.ErrNoRows) && attempt == 0 {
+			continue
+		}
+		if err != nil {
+			return db.ContentBlob{}, fmt.Errorf("failed to lock blob: %w", err)
+		}
+		return blob, nil
+	}
+}
+
+func (i *PostgresContentIndex) withTx(ctx context.Context, fn func(q *db.Queries) error) error {
+	tx, err := i.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(i.q.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,48 @@
+-- internal/shared/storage/casindex/query.sql
+
+-- name: InsertBlobIfAbsent :exec
+INSERT INTO content_blobs (id, digest, size_bytes, content_type)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (digest) DO NOTHING;
+
+-- name: LockBlobByDigest :one
+SELECT * FROM content_blobs WHERE digest = $1 FOR UPDATE;
+
+-- name: IncrementBlobReferences :exec
+UPDATE content_blobs
+SET reference_count = reference_count + 1, unreferenced_at = NULL
+WHERE id = $1;
+
+-- name: DecrementBlobReferences :exec
+UPDATE content_blobs
+SET reference_count = reference_count - 1,
+    unreferenced_at = CASE WHEN reference_count - 1 = 0 THEN NOW() ELSE NULL END
+WHERE id = $1;
+
+-- name: GetReferenceForUpdate :one
+SELECT * FROM content_references WHERE namespace = $1 AND object_key = $2 FOR UPDATE;
+
+-- name: UpsertReference :exec
+INSERT INTO content_references (id, namespace, object_key, content_blob_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (namespace, object_key)
+DO UPDATE SET content_blob_id = EXCLUDED.content_blob_id, updated_at = NOW();
+
+-- name: DeleteReference :one
+DELETE FROM content_references WHERE namespace = $1 AND object_key = $2 RETURNING content_blob_id;
+
+-- name: GetReference :one
+SELECT r.namespace, r.object_key, b.digest, b.size_bytes, b.content_type
+FROM content_references r
+JOIN content_blobs b ON b.id = r.content_blob_id
+WHERE r.namespace = $1 AND r.object_key = $2;
+
+-- name: LockCollectableBlobs :many
+SELECT id, digest FROM content_blobs
+WHERE reference_count = 0 AND unreferenced_at < $1
+ORDER BY unreferenced_at
+LIMIT $2
+FOR UPDATE SKIP LOCKED;
+
+-- name: DeleteBlob :exec
+DELETE FROM content_blobs WHERE id = $1;
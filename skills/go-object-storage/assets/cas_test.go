@@ -0,0 +1,229 @@
+// internal/shared/storage/cas_test.go
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"api/booking/internal/shared/storage"
+	"api/booking/internal/shared/storage/casindex"
+)
+
+// collectNow makes every unreferenced blob collectable.
+const collectNow = -time.Minute
+
+func newContentStore(t *testing.T) (*storage.ContentStore, *storage.InMemoryStore) {
+	t.Helper()
+	inner := storage.NewInMemoryStore()
+	return storage.NewContentStore(inner, casindex.NewInMemoryContentIndex(), bucket, "backups"), inner
+}
+
+func put(t *testing.T, cas *storage.ContentStore, key, content string) *storage.BlobRef {
+	t.Helper()
+	ref, err := cas.Put(context.Background(), key, strings.NewReader(content), storage.PutOptions{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("Put(%s) error = %v", key, err)
+	}
+	return ref
+}
+
+func blobCount(t *testing.T, inner *storage.InMemoryStore) int {
+	t.Helper()
+	blobs, err := inner.List(context.Background(), bucket, "blobs/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(blobs)
+}
+
+func collect(t *testing.T, cas *storage.ContentStore, grace time.Duration) int {
+	t.Helper()
+	n, err := cas.CollectGarbage(context.Background(), grace)
+	if err != nil {
+		t.Fatalf("CollectGarbage() error = %v", err)
+	}
+	return n
+}
+
+func TestContentStore_ReferenceCounting(t *testing.T) {
+	ctx := context.Background()
+	cas, inner := newContentStore(t)
+
+	a := put(t, cas, "a", "same content")
+	b := put(t, cas, "b", "same content")
+	put(t, cas, "b", "same content") // Re-putting a key doesn't add a reference
+	if a.Digest != b.Digest {
+		t.Errorf("digests differ for identical content: %s, %s", a.Digest, b.Digest)
+	}
+	if n := blobCount(t, inner); n != 1 {
+		t.Fatalf("%d blobs stored, want 1", n)
+	}
+
+	if err := cas.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if n := collect(t, cas, collectNow); n != 0 {
+		t.Errorf("CollectGarbage() removed %d blobs still referenced by b", n)
+	}
+	body, err := cas.Get(ctx, "b")
+	if err != nil {
+		t.Fatalf("Get(b) error = %v", err)
+	}
+	got, _ := io.ReadAll(body)
+	body.Close()
+	if string(got) != "same content" {
+		t.Errorf("Get(b) = %q", got)
+	}
+
+	put(t, cas, "b", "new content") // Overwriting releases the old blob
+	if n := collect(t, cas, time.Hour); n != 0 {
+		t.Errorf("CollectGarbage() removed %d blobs within the grace period", n)
+	}
+	if n := collect(t, cas, collectNow); n != 1 {
+		t.Errorf("CollectGarbage() removed %d blobs, want the released one", n)
+	}
+	if n := blobCount(t, inner); n != 1 {
+		t.Errorf("%d blobs stored, want only b's new content", n)
+	}
+
+	if err := cas.Delete(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cas.Delete(ctx, "b"); !errors.Is(err, storage.ErrRefNotFound) {
+		t.Errorf("Delete() twice: error = %v, want ErrRefNotFound", err)
+	}
+	if _, err := cas.Stat(ctx, "b"); !errors.Is(err, storage.ErrRefNotFound) {
+		t.Errorf("Stat() after Delete: error = %v, want ErrRefNotFound", err)
+	}
+	collect(t, cas, collectNow)
+	if n := blobCount(t, inner); n != 0 {
+		t.Errorf("%d blobs left after every key was deleted", n)
+	}
+}
+
+// gcOnFirstExists runs the collector right after the first existence check,
+// the window between a writer's upload and its link.
+type gcOnFirstExists struct {
+	storage.ObjectStore
+	once sync.Once
+	gc   func()
+}
+
+func (s *gcOnFirstExists) Exists(ctx context.Context, bucket, key string) (bool, error) {
+	exists, err := s.ObjectStore.Exists(ctx, bucket, key)
+	s.once.Do(s.gc)
+	return exists, err
+}
+
+func TestContentStore_PutRacingGarbageCollection(t *testing.T) {
+	ctx := context.Background()
+	inner := storage.NewInMemoryStore()
+	index := casindex.NewInMemoryContentIndex()
+	setup := storage.NewContentStore(inner, index, bucket, "backups")
+	put(t, setup, "old", "content")
+	if err := setup.Delete(ctx, "old"); err != nil {
+		t.Fatal(err)
+	}
+
+	var collected int
+	hooked := &gcOnFirstExists{ObjectStore: inner}
+	hooked.gc = func() { collected = collect(t, setup, collectNow) }
+	cas := storage.NewContentStore(hooked, index, bucket, "backups")
+
+	put(t, cas, "new", "content")
+	if collected != 1 {
+		t.Fatalf("collector removed %d blobs mid-Put, want 1", collected)
+	}
+	body, err := cas.Get(ctx, "new")
+	if err != nil {
+		t.Fatalf("Get() after a racing collection: error = %v", err)
+	}
+	body.Close()
+	if n := collect(t, cas, collectNow); n != 0 {
+		t.Errorf("CollectGarbage() removed %d blobs of a linked key", n)
+	}
+}
+
+func TestContentStore_ConcurrentWritersAndCollectors(t *testing.T) {
+	ctx := context.Background()
+	cas, _ := newContentStore(t)
+	contents := []string{"alpha", "beta", "gamma"}
+
+	var (
+		wg   sync.WaitGroup
+		stop = make(chan struct{})
+	)
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if _, err := cas.CollectGarbage(ctx, collectNow); err != nil {
+					t.Errorf("CollectGarbage() error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	var writers sync.WaitGroup
+	for w := range 8 {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			for i := range 50 {
+				key := fmt.Sprintf("w%d-%d", w, i%3)
+				content := contents[(w+i)%len(contents)]
+				if _, err := cas.Put(ctx, key, strings.NewReader(content), storage.PutOptions{}); err != nil {
+					t.Errorf("Put() error = %v", err)
+					return
+				}
+				if i%2 == 1 {
+					if err := cas.Delete(ctx, key); err != nil {
+						t.Errorf("Delete() error = %v", err)
+						return
+					}
+				}
+			}
+		}()
+	}
+	writers.Wait()
+	close(stop)
+	wg.Wait()
+	collect(t, cas, collectNow)
+
+	// Every key still linked must resolve to its full content.
+	for w := range 8 {
+		for i := range 3 {
+			key := fmt.Sprintf("w%d-%d", w, i)
+			ref, err := cas.Stat(ctx, key)
+			if errors.Is(err, storage.ErrRefNotFound) {
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := cas.Get(ctx, key)
+			if err != nil {
+				t.Fatalf("Get(%s) error = %v; blob collected while referenced", key, err)
+			}
+			got, _ := io.ReadAll(body)
+			body.Close()
+			if int64(len(got)) != ref.Size || !bytes.Contains([]byte(strings.Join(contents, " ")), got) {
+				t.Errorf("Get(%s) = %q", key, got)
+			}
+		}
+	}
+}
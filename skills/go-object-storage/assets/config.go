@@ -1,18 +1,7 @@
-type StorageConfig struct {
-    Provider       string // "s3", "gcs", "minio"
-    Endpoint       string // Empty for AWS/GCS, URL for MinIO
-    Region         string
-    ForcePathStyle bool   // true for MinIO
-    Buckets        BucketConfig
-}
-
-type BucketConfig struct {
-    Uploads string // e.g., "bastet-uploads"
-    Exports string // e.g., "bastet-exports"
-    Backups string // e.g., "bastet-backups"
-}
-
-func NewObjectStore(ctx context.Context, cfg StorageConfig) (ObjectStore, error) {
+// StorageConfig lives in internal/shared/config with the rest of the
+// environment: provider, buckets, STORAGE_KEY_FILE, the webhook token and
+// lifecycle rules are all read by config.Load.
+func NewObjectStore(ctx context.Context, cfg config.StorageConfig) (ObjectStore, error) {
     switch cfg.Provider {
     case "s3", "minio":
         return NewS3Store(ctx, cfg.Endpoint, cfg.Region, cfg.ForcePathStyle)
@@ -0,0 +1,251 @@
+// internal/shared/storage/encrypted.go
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"time"
+)
+
+// Metadata keys written on every encrypted object. Providers lowercase user
+// metadata, so keep them lowercase.
+const (
+	metaEncAlgorithm = "enc-alg"
+	metaEncKeyID     = "enc-key-id"
+	metaEncDataKey   = "enc-data-key" // Data key wrapped by the KEK, base64
+	metaEncNonce     = "enc-nonce"    // Chunk nonce prefix, base64
+)
+
+var (
+	ErrObjectNotEncrypted    = errors.New("storage: object is not encrypted")
+	ErrSignedURLNotSupported = errors.New("storage: signed URLs would expose ciphertext")
+)
+
+// EncryptedStore is an ObjectStore decorator that encrypts objects client-side
+// with envelope encryption, independent of bucket settings:
+//
+//   - a fresh 256-bit data key per object encrypts the body (streaming AES-GCM)
+//   - the data key is wrapped by a KeyManager KEK and stored in object metadata
+//   - rotating a KEK only re-wraps data keys; bodies are never re-encrypted
+//
+// Existence checks and deletes pass through to the inner store; Head and List
+// hide the encryption metadata. Sizes they report are ciphertext sizes. The
+// inner store must implement HeadStore, ObjectReader and Copier, as S3Store
+// and InMemoryStore do.
+type EncryptedStore struct {
+	ObjectStore
+	keys KeyManager
+}
+
+func NewEncryptedStore(inner ObjectStore, keys KeyManager) *EncryptedStore {
+	return &EncryptedStore{ObjectStore: inner, keys: keys}
+}
+
+func (s *EncryptedStore) Put(ctx context.Context, bucket, key string, reader io.Reader, opts PutOptions) error {
+	dataKey := make([]byte, 32)
+	prefix := make([]byte, encPrefixSize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return fmt.Errorf("failed to generate data key: %w", err)
+	}
+	if _, err := rand.Read(prefix); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	keyID := s.keys.CurrentKeyID()
+	wrapped, err := s.keys.WrapKey(ctx, keyID, dataKey)
+	if err != nil {
+		return fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return err
+	}
+
+	metadata := maps.Clone(opts.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]string, 4)
+	}
+	metadata[metaEncAlgorithm] = encAlgorithm
+	metadata[metaEncKeyID] = keyID
+	metadata[metaEncDataKey] = base64.StdEncoding.EncodeToString(wrapped)
+	metadata[metaEncNonce] = base64.StdEncoding.EncodeToString(prefix)
+
+	return s.ObjectStore.Put(ctx, bucket, key, newStreamEncrypter(aead, prefix, reader), PutOptions{
+		ContentType: opts.ContentType,
+		Metadata:    metadata,
+	})
+}
+
+// Get reads the wrapped data key from the same response as the body, so an
+// overwrite between two requests can't pair a body with another object's key.
+// The inner store must implement ObjectReader.
+func (s *EncryptedStore) Get(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	reader, ok := s.ObjectStore.(ObjectReader)
+	if !ok {
+		return nil, fmt.Errorf("encrypted store needs an ObjectReader, got %T", s.ObjectStore)
+	}
+	body, info, err := reader.GetWithInfo(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, prefix, err := s.unwrap(ctx, info.Metadata)
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("%s/%s: %w", bucket, key, err)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	return newStreamDecrypter(aead, prefix, body), nil
+}
+
+// Head hides the encryption metadata from callers.
+func (s *EncryptedStore) Head(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	info, err := Head(ctx, s.ObjectStore, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	stripEncMetadata(info)
+	return info, nil
+}
+
+// List and ListPage hide the encryption metadata like Head.
+func (s *EncryptedStore) List(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	for obj, err := range All(ctx, s, bucket, ListOptions{Prefix: prefix}) {
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+func (s *EncryptedStore) ListPage(ctx context.Context, bucket string, opts ListOptions) (*ListResult, error) {
+	result, err := s.ObjectStore.ListPage(ctx, bucket, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result.Objects {
+		stripEncMetadata(&result.Objects[i])
+	}
+	return result, nil
+}
+
+// SignedURL is refused: a presigned GET would hand the client ciphertext.
+// Serve decrypted content through the API instead.
+func (s *EncryptedStore) SignedURL(ctx context.Context, bucket, key string, expiry time.Duration) (string, error) {
+	return "", ErrSignedURLNotSupported
+}
+
+// Rewrap re-wraps the object's data key with the current KEK. Only the
+// metadata is rewritten, with a server-side copy conditional on the ETag read
+// alongside the old key: if the object was replaced in between, the copy
+// fails with ErrPreconditionFailed instead of pairing the new body with the
+// old data key. Returns false if the object already uses the current key, and
+// ErrObjectNotEncrypted for plaintext objects. The inner store must implement
+// Copier.
+func (s *EncryptedStore) Rewrap(ctx context.Context, bucket, key string) (bool, error) {
+	copier, ok := s.ObjectStore.(Copier)
+	if !ok {
+		return false, ErrCopyNotSupported
+	}
+	info, err := Head(ctx, s.ObjectStore, bucket, key)
+	if err != nil {
+		return false, err
+	}
+	current := s.keys.CurrentKeyID()
+	if info.Metadata[metaEncKeyID] == current {
+		return false, nil
+	}
+
+	dataKey, _, err := s.unwrap(ctx, info.Metadata)
+	if err != nil {
+		return false, fmt.Errorf("%s/%s: %w", bucket, key, err)
+	}
+	wrapped, err := s.keys.WrapKey(ctx, current, dataKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	metadata := maps.Clone(info.Metadata)
+	metadata[metaEncKeyID] = current
+	metadata[metaEncDataKey] = base64.StdEncoding.EncodeToString(wrapped)
+
+	err = copier.Copy(ctx, bucket, key, key, CopyOptions{
+		ContentType: info.ContentType,
+		Metadata:    metadata,
+		IfMatch:     info.ETag,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to rewrite %s/%s: %w", bucket, key, err)
+	}
+	return true, nil
+}
+
+type RewrapReport struct {
+	Rewrapped   int // Moved to the current KEK
+	Unencrypted int // Plaintext objects under the prefix, skipped
+}
+
+// RewrapAll rotates every object under prefix to the current KEK. Run it after
+// adding a new key as "current"; remove the old key once Rewrapped is zero.
+// An object overwritten mid-rotation is retried once, since the writer may
+// have used the previous KEK.
+func (s *EncryptedStore) RewrapAll(ctx context.Context, bucket, prefix string) (RewrapReport, error) {
+	var report RewrapReport
+	for obj, err := range All(ctx, s.ObjectStore, bucket, ListOptions{Prefix: prefix}) {
+		if err != nil {
+			return report, err
+		}
+		changed, err := s.Rewrap(ctx, bucket, obj.Key)
+		if errors.Is(err, ErrPreconditionFailed) {
+			changed, err = s.Rewrap(ctx, bucket, obj.Key)
+		}
+		switch {
+		case errors.Is(err, ErrObjectNotEncrypted):
+			report.Unencrypted++
+		case errors.Is(err, ErrObjectNotFound):
+			// Deleted since it was listed.
+		case err != nil:
+			return report, err
+		case changed:
+			report.Rewrapped++
+		}
+	}
+	return report, nil
+}
+
+func stripEncMetadata(info *ObjectInfo) {
+	info.Metadata = maps.Clone(info.Metadata)
+	for _, k := range []string{metaEncAlgorithm, metaEncKeyID, metaEncDataKey, metaEncNonce} {
+		delete(info.Metadata, k)
+	}
+}
+
+func (s *EncryptedStore) unwrap(ctx context.Context, metadata map[string]string) (dataKey, prefix []byte, err error) {
+	if metadata[metaEncAlgorithm] != encAlgorithm {
+		return nil, nil, ErrObjectNotEncrypted
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(metadata[metaEncDataKey])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid wrapped data key: %w", err)
+	}
+	prefix, err = base64.StdEncoding.DecodeString(metadata[metaEncNonce])
+	if err != nil || len(prefix) != encPrefixSize {
+		return nil, nil, errors.New("invalid nonce prefix")
+	}
+	dataKey, err = s.keys.UnwrapKey(ctx, metadata[metaEncKeyID], wrapped)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dataKey, prefix, nil
+}
@@ -0,0 +1,174 @@
+// internal/shared/storage/encrypted_stream.go
+package storage
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Objects are encrypted as a sequence of independent AES-GCM chunks (the
+// STREAM construction), so arbitrarily large objects never need to fit in
+// memory. Each chunk's nonce is:
+//
+//	prefix (7 random bytes) || chunk counter (uint32 BE) || last-chunk flag (1 byte)
+//
+// The flag makes truncation at a chunk boundary fail authentication, and the
+// counter makes reordering fail.
+const (
+	encChunkSize   = 64 << 10
+	encPrefixSize  = 7
+	encAlgorithm   = "AES256-GCM-STREAM-64K"
+	encNonceLength = encPrefixSize + 4 + 1
+)
+
+var errTooManyChunks = errors.New("storage: encrypted stream exceeds chunk limit")
+
+type chunkNonce struct {
+	prefix  []byte
+	counter uint32
+	done    bool
+}
+
+func (n *chunkNonce) next(last bool) ([]byte, error) {
+	if n.done {
+		return nil, errTooManyChunks
+	}
+	nonce := make([]byte, encNonceLength)
+	copy(nonce, n.prefix)
+	binary.BigEndian.PutUint32(nonce[encPrefixSize:], n.counter)
+	if last {
+		nonce[encNonceLength-1] = 1
+	}
+	if n.counter == math.MaxUint32 {
+		n.done = true
+	}
+	n.counter++
+	return nonce, nil
+}
+
+// streamEncrypter turns a plaintext reader into a ciphertext reader.
+type streamEncrypter struct {
+	aead  cipher.AEAD
+	nonce chunkNonce
+	src   *bufio.Reader
+	plain []byte
+	buf   []byte
+	out   []byte
+	done  bool
+}
+
+func newStreamEncrypter(aead cipher.AEAD, prefix []byte, src io.Reader) *streamEncrypter {
+	return &streamEncrypter{
+		aead:  aead,
+		nonce: chunkNonce{prefix: prefix},
+		src:   bufio.NewReaderSize(src, encChunkSize),
+		plain: make([]byte, encChunkSize),
+		buf:   make([]byte, 0, encChunkSize+aead.Overhead()),
+	}
+}
+
+func (e *streamEncrypter) Read(p []byte) (int, error) {
+	for len(e.out) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		if err := e.sealNext(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, e.out)
+	e.out = e.out[n:]
+	return n, nil
+}
+
+func (e *streamEncrypter) sealNext() error {
+	n, last, err := readChunk(e.src, e.plain)
+	if err != nil {
+		return err
+	}
+	nonce, err := e.nonce.next(last)
+	if err != nil {
+		return err
+	}
+	e.out = e.aead.Seal(e.buf[:0], nonce, e.plain[:n], nil)
+	e.done = last
+	return nil
+}
+
+// streamDecrypter verifies and decrypts a stream produced by streamEncrypter.
+type streamDecrypter struct {
+	aead   cipher.AEAD
+	nonce  chunkNonce
+	src    *bufio.Reader
+	closer io.Closer
+	sealed []byte
+	out    []byte
+	done   bool
+}
+
+func newStreamDecrypter(aead cipher.AEAD, prefix []byte, src io.ReadCloser) *streamDecrypter {
+	return &streamDecrypter{
+		aead:   aead,
+		nonce:  chunkNonce{prefix: prefix},
+		src:    bufio.NewReaderSize(src, encChunkSize+aead.Overhead()),
+		closer: src,
+		sealed: make([]byte, encChunkSize+aead.Overhead()),
+	}
+}
+
+func (d *streamDecrypter) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.openNext(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+func (d *streamDecrypter) openNext() error {
+	n, last, err := readChunk(d.src, d.sealed)
+	if err != nil {
+		return err
+	}
+	nonce, err := d.nonce.next(last)
+	if err != nil {
+		return err
+	}
+	plain, err := d.aead.Open(d.sealed[:0], nonce, d.sealed[:n], nil)
+	if err != nil {
+		return fmt.Errorf("storage: encrypted chunk %d failed authentication: %w", d.nonce.counter-1, err)
+	}
+	d.out = plain
+	d.done = last
+	return nil
+}
+
+func (d *streamDecrypter) Close() error { return d.closer.Close() }
+
+// readChunk fills buf and reports whether it is the final chunk by peeking one
+// byte ahead.
+func readChunk(r *bufio.Reader, buf []byte) (n int, last bool, err error) {
+	n, err = io.ReadFull(r, buf)
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return n, true, nil
+	case err != nil:
+		return 0, false, fmt.Errorf("failed to read chunk: %w", err)
+	}
+	if _, err := r.Peek(1); errors.Is(err, io.EOF) {
+		return n, true, nil
+	} else if err != nil {
+		return 0, false, fmt.Errorf("failed to read chunk: %w", err)
+	}
+	return n, false, nil
+}
@@ -0,0 +1,239 @@
+// internal/shared/storage/encrypted_test.go
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"api/booking/internal/shared/storage"
+)
+
+const (
+	bucket = "bastet-documents"
+	// sealedChunk is one full ciphertext chunk: 64 KiB plus the GCM tag.
+	sealedChunk = 64<<10 + 16
+)
+
+func TestEncryptedStore_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{"empty", 0},
+		{"partial chunk", 1000},
+		{"exact chunk", 64 << 10},
+		{"several chunks", 3*64<<10 + 123},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			inner := storage.NewInMemoryStore()
+			store := storage.NewEncryptedStore(inner, newKeyManager(t))
+			plain := randomBytes(t, tt.size)
+
+			if err := store.Put(ctx, bucket, "doc", bytes.NewReader(plain), storage.PutOptions{Metadata: map[string]string{"owner": "ana"}}); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			if raw := readAll(t, inner, "doc"); tt.size > 0 && bytes.Contains(raw, plain) {
+				t.Error("plaintext stored in the clear")
+			}
+			if got := readAll(t, store, "doc"); !bytes.Equal(got, plain) {
+				t.Errorf("Get() returned %d bytes, want the %d written", len(got), len(plain))
+			}
+
+			info, err := store.Head(ctx, bucket, "doc")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(info.Metadata) != 1 || info.Metadata["owner"] != "ana" {
+				t.Errorf("Head() metadata = %v, want only the caller's", info.Metadata)
+			}
+		})
+	}
+}
+
+func TestEncryptedStore_RejectsTamperedStreams(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(raw []byte) []byte
+	}{
+		{"flipped ciphertext bit", func(raw []byte) []byte {
+			raw[sealedChunk+10] ^= 1
+			return raw
+		}},
+		{"flipped tag bit", func(raw []byte) []byte {
+			raw[sealedChunk-1] ^= 1
+			return raw
+		}},
+		{"truncated at a chunk boundary", func(raw []byte) []byte {
+			return raw[:2*sealedChunk]
+		}},
+		{"truncated mid chunk", func(raw []byte) []byte {
+			return raw[:2*sealedChunk+100]
+		}},
+		{"last chunk dropped", func(raw []byte) []byte {
+			return raw[:3*sealedChunk]
+		}},
+		{"chunks reordered", func(raw []byte) []byte {
+			out := bytes.Clone(raw)
+			copy(out[:sealedChunk], raw[sealedChunk:2*sealedChunk])
+			copy(out[sealedChunk:2*sealedChunk], raw[:sealedChunk])
+			return out
+		}},
+		{"chunk duplicated", func(raw []byte) []byte {
+			out := bytes.Clone(raw[:sealedChunk])
+			return append(out, raw...)
+		}},
+		{"bytes appended", func(raw []byte) []byte {
+			return append(raw, 0)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			inner := storage.NewInMemoryStore()
+			store := storage.NewEncryptedStore(inner, newKeyManager(t))
+			plain := randomBytes(t, 3*64<<10+123) // Four chunks, the last partial
+			if err := store.Put(ctx, bucket, "doc", bytes.NewReader(plain), storage.PutOptions{}); err != nil {
+				t.Fatal(err)
+			}
+
+			info, err := inner.Head(ctx, bucket, "doc")
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw := tt.tamper(readAll(t, inner, "doc"))
+			if err := inner.Put(ctx, bucket, "doc", bytes.NewReader(raw), storage.PutOptions{Metadata: info.Metadata}); err != nil {
+				t.Fatal(err)
+			}
+
+			body, err := store.Get(ctx, bucket, "doc")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer body.Close()
+			if got, err := io.ReadAll(body); err == nil {
+				t.Errorf("Get() read %d bytes of a tampered stream without error", len(got))
+			}
+		})
+	}
+}
+
+func TestEncryptedStore_Rewrap(t *testing.T) {
+	ctx := context.Background()
+	inner := storage.NewInMemoryStore()
+	keyFile := filepath.Join(t.TempDir(), "keys.json")
+	oldKek, newKek := randomBytes(t, 32), randomBytes(t, 32)
+
+	writeKeyFile(t, keyFile, "k1", map[string][]byte{"k1": oldKek})
+	old := storage.NewEncryptedStore(inner, loadKeys(t, keyFile))
+	plain := randomBytes(t, 100<<10)
+	for _, key := range []string{"a", "b"} {
+		if err := old.Put(ctx, bucket, key, bytes.NewReader(plain), storage.PutOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := inner.Put(ctx, bucket, "plain", bytes.NewReader(plain), storage.PutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	before := readAll(t, inner, "a")
+
+	writeKeyFile(t, keyFile, "k2", map[string][]byte{"k1": oldKek, "k2": newKek})
+	rotated := storage.NewEncryptedStore(inner, loadKeys(t, keyFile))
+
+	changed, err := rotated.Rewrap(ctx, bucket, "a")
+	if err != nil || !changed {
+		t.Fatalf("Rewrap() = %v, %v; want true", changed, err)
+	}
+	if changed, err := rotated.Rewrap(ctx, bucket, "a"); err != nil || changed {
+		t.Errorf("Rewrap() again = %v, %v; want false", changed, err)
+	}
+	if _, err := rotated.Rewrap(ctx, bucket, "plain"); !errors.Is(err, storage.ErrObjectNotEncrypted) {
+		t.Errorf("Rewrap(plain) error = %v, want ErrObjectNotEncrypted", err)
+	}
+	if !bytes.Equal(readAll(t, inner, "a"), before) {
+		t.Error("Rewrap() rewrote the body; only the data key should change")
+	}
+
+	report, err := rotated.RewrapAll(ctx, bucket, "")
+	if err != nil {
+		t.Fatalf("RewrapAll() error = %v", err)
+	}
+	if report != (storage.RewrapReport{Rewrapped: 1, Unencrypted: 1}) {
+		t.Errorf("RewrapAll() = %+v, want 1 rewrapped and 1 unencrypted", report)
+	}
+
+	// Once every object is rewrapped the old KEK can be removed.
+	writeKeyFile(t, keyFile, "k2", map[string][]byte{"k2": newKek})
+	current := storage.NewEncryptedStore(inner, loadKeys(t, keyFile))
+	for _, key := range []string{"a", "b"} {
+		if got := readAll(t, current, key); !bytes.Equal(got, plain) {
+			t.Errorf("Get(%s) after rotation returned other content", key)
+		}
+	}
+}
+
+func newKeyManager(t *testing.T) *storage.LocalKeyManager {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	writeKeyFile(t, path, "k1", map[string][]byte{"k1": randomBytes(t, 32)})
+	return loadKeys(t, path)
+}
+
+func writeKeyFile(t *testing.T, path, current string, keys map[string][]byte) {
+	t.Helper()
+	file := struct {
+		Current string            `json:"current"`
+		Keys    map[string]string `json:"keys"`
+	}{Current: current, Keys: make(map[string]string, len(keys))}
+	for id, kek := range keys {
+		file.Keys[id] = base64.StdEncoding.EncodeToString(kek)
+	}
+	content, err := json.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadKeys(t *testing.T, path string) *storage.LocalKeyManager {
+	t.Helper()
+	keys, err := storage.NewLocalKeyManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func readAll(t *testing.T, store storage.ObjectStore, key string) []byte {
+	t.Helper()
+	body, err := store.Get(context.Background(), bucket, key)
+	if err != nil {
+		t.Fatalf("Get(%s) error = %v", key, err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("Get(%s) read error = %v", key, err)
+	}
+	return data
+}
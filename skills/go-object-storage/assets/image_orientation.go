@@ -0,0 +1,48 @@
+// internal/upload/infrastructure/imaging/orientation.go
+package imaging
+
+import "image"
+
+// applyOrientation bakes the EXIF orientation into the pixels. It must run
+// before metadata is stripped, or phone photos end up sideways.
+func applyOrientation(src image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 { // 90° variants swap dimensions
+		dw, dh = h, w
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx, dy := orientedPoint(orientation, x, y, w, h)
+			dst.Set(dx, dy, src.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+func orientedPoint(orientation, x, y, w, h int) (int, int) {
+	switch orientation {
+	case 2: // mirror horizontal
+		return w - 1 - x, y
+	case 3: // rotate 180
+		return w - 1 - x, h - 1 - y
+	case 4: // mirror vertical
+		return x, h - 1 - y
+	case 5: // transpose
+		return y, x
+	case 6: // rotate 90 CW
+		return h - 1 - y, x
+	case 7: // transverse
+		return h - 1 - y, w - 1 - x
+	case 8: // rotate 270 CW
+		return y, w - 1 - x
+	}
+	return x, y
+}
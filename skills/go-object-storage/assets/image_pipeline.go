@@ -0,0 +1,271 @@
+// internal/upload/infrastructure/imaging/pipeline.go
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"api/caregiver/internal/shared/storage"
+	"api/caregiver/internal/upload/domain"
+	"github.com/google/uuid"
+	"github.com/rwcarlsen/goexif/exif"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Registers the WebP decoder with image.Decode
+)
+
+// Pure Go only — no cgo (libvips, ImageMagick), so it runs in distroless images.
+
+var ErrUnsupportedImage = errors.New("imaging: unsupported or invalid image")
+
+type ThumbnailSize struct {
+	Name   string // Key suffix, e.g. "sm" → photo-3f2a_sm.jpg
+	MaxDim int    // Longest side in pixels
+}
+
+type Config struct {
+	Bucket      string   // BucketConfig.Uploads — uploads to other buckets are ignored
+	Purposes    []string // Upload purposes to process; default DefaultImagePurposes
+	Thumbnails  []ThumbnailSize
+	MaxPixels   int // Decompression-bomb guard; default 40 MP
+	JPEGQuality int // Default 85
+}
+
+// DefaultImagePurposes leaves out caregiver_document: identity documents
+// are stored encrypted and must never get plaintext derivatives.
+var DefaultImagePurposes = []string{"pet_photo"}
+
+var DefaultThumbnails = []ThumbnailSize{
+	{Name: "sm", MaxDim: 256},
+	{Name: "md", MaxDim: 768},
+	{Name: "lg", MaxDim: 1600},
+}
+
+// Pipeline sanitizes and derives thumbnails for confirmed image uploads.
+// It implements application.UploadProcessor.
+type Pipeline struct {
+	store  storage.ObjectStore
+	events domain.EventPublisher
+	cfg    Config
+}
+
+func NewPipeline(store storage.ObjectStore, events domain.EventPublisher, cfg Config) *Pipeline {
+	if cfg.Purposes == nil {
+		cfg.Purposes = DefaultImagePurposes
+	}
+	if cfg.MaxPixels == 0 {
+		cfg.MaxPixels = 40_000_000
+	}
+	if cfg.JPEGQuality == 0 {
+		cfg.JPEGQuality = 85
+	}
+	return &Pipeline{store: store, events: events, cfg: cfg}
+}
+
+// Process runs: sniff → decode → apply EXIF orientation → rewrite original
+// without metadata (drops EXIF/GPS) → thumbnails → publish event. Invalid
+// images are deleted and reported with domain.ErrUploadRejected.
+func (p *Pipeline) Process(ctx context.Context, upload *domain.Upload) error {
+	if upload.Bucket != p.cfg.Bucket || !slices.Contains(p.cfg.Purposes, upload.Purpose) || !supported(upload.ContentType) {
+		return nil
+	}
+
+	data, err := p.read(ctx, upload)
+	if err != nil {
+		return err
+	}
+
+	img, err := p.decode(data, upload.ContentType)
+	if err != nil {
+		p.quarantine(ctx, upload, err)
+		return fmt.Errorf("%w: %w", domain.ErrUploadRejected, err)
+	}
+
+	original, err := p.strip(data, img, upload.ContentType)
+	if err != nil {
+		p.quarantine(ctx, upload, err)
+		return fmt.Errorf("%w: %w", domain.ErrUploadRejected, err)
+	}
+	// Keep the slot's metadata so a retried Confirm still matches upload-id.
+	if err := p.put(ctx, upload.Key, original, upload.ContentType, upload.ObjectMetadata()); err != nil {
+		return err
+	}
+
+	thumbType := thumbnailType(upload.ContentType)
+	thumbnails := make(map[string]string, len(p.cfg.Thumbnails))
+	for _, size := range p.cfg.Thumbnails {
+		key := storage.DerivedKey(upload.Key, size.Name, extension(thumbType))
+		data, err := p.encode(fit(img, size.MaxDim), thumbType)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+		if err := p.put(ctx, key, data, thumbType, upload.ObjectMetadata()); err != nil {
+			return err
+		}
+		thumbnails[size.Name] = key
+	}
+
+	bounds := img.Bounds()
+	return p.events.Publish(ctx, domain.EventImageProcessed, domain.Event{
+		ID:        uuid.NewString(),
+		Type:      domain.EventImageProcessed,
+		Timestamp: time.Now(),
+		Data: domain.ImageProcessedEvent{
+			UploadID:    upload.ID,
+			OwnerID:     upload.OwnerID,
+			Bucket:      upload.Bucket,
+			Key:         upload.Key,
+			ContentType: upload.ContentType,
+			Width:       bounds.Dx(),
+			Height:      bounds.Dy(),
+			Thumbnails:  thumbnails,
+		},
+	})
+}
+
+func (p *Pipeline) read(ctx context.Context, upload *domain.Upload) ([]byte, error) {
+	body, err := p.store.Get(ctx, upload.Bucket, upload.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", upload.Key, err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, upload.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", upload.Key, err)
+	}
+	if int64(len(data)) > upload.MaxSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrUnsupportedImage, upload.MaxSize)
+	}
+	return data, nil
+}
+
+// decode trusts the bytes, not the declared type or extension.
+func (p *Pipeline) decode(data []byte, declared string) (image.Image, error) {
+	if sniffed := http.DetectContentType(data); sniffed != declared {
+		return nil, fmt.Errorf("%w: declared %s, sniffed %s", ErrUnsupportedImage, declared, sniffed)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if cfg.Width*cfg.Height > p.cfg.MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrUnsupportedImage, cfg.Width, cfg.Height, p.cfg.MaxPixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if declared == "image/jpeg" {
+		img = applyOrientation(img, exifOrientation(data))
+	}
+	return img, nil
+}
+
+// strip returns the original without EXIF/GPS. JPEG and PNG are re-encoded —
+// Go encoders write no metadata. There is no pure-Go WebP encoder, so WebP
+// keeps its pixels and only loses its metadata chunks.
+func (p *Pipeline) strip(data []byte, img image.Image, contentType string) ([]byte, error) {
+	if contentType == "image/webp" {
+		return stripWebPMetadata(data)
+	}
+	return p.encode(img, contentType)
+}
+
+func (p *Pipeline) encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch contentType {
+	case "image/jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.cfg.JPEGQuality})
+	case "image/png":
+		err = png.Encode(&buf, img)
+	default:
+		err = fmt.Errorf("no encoder for %s", contentType)
+	}
+	return buf.Bytes(), err
+}
+
+func (p *Pipeline) put(ctx context.Context, key string, data []byte, contentType string, metadata map[string]string) error {
+	opts := storage.PutOptions{ContentType: contentType, Metadata: metadata}
+	if err := p.store.Put(ctx, p.cfg.Bucket, key, bytes.NewReader(data), opts); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+// quarantine deletes objects that are not the images they claim to be.
+func (p *Pipeline) quarantine(ctx context.Context, upload *domain.Upload, reason error) {
+	slog.WarnContext(ctx, "rejecting invalid image upload",
+		"error", reason,
+		"upload_id", upload.ID,
+		"key", upload.Key,
+	)
+	if err := p.store.Delete(ctx, upload.Bucket, upload.Key); err != nil {
+		slog.ErrorContext(ctx, "failed to delete invalid image", "error", err, "key", upload.Key)
+	}
+}
+
+// fit scales img so its longest side is at most maxDim, keeping aspect ratio.
+func fit(img image.Image, maxDim int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxDim && h <= maxDim {
+		return img
+	}
+	if w >= h {
+		h, w = h*maxDim/w, maxDim
+	} else {
+		w, h = w*maxDim/h, maxDim
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, max(w, 1), max(h, 1)))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+func exifOrientation(data []byte) int {
+	x, err := exif.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 1
+	}
+	tag, err := x.Get(exif.Orientation)
+	if err != nil {
+		return 1
+	}
+	o, err := tag.Int(0)
+	if err != nil {
+		return 1
+	}
+	return o
+}
+
+func supported(contentType string) bool {
+	return contentType == "image/jpeg" || contentType == "image/png" || contentType == "image/webp"
+}
+
+// thumbnailType is the format thumbnails are written in: WebP can only be
+// decoded, so its thumbnails are JPEG.
+func thumbnailType(contentType string) string {
+	if contentType == "image/png" {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
+
+func extension(contentType string) string {
+	if contentType == "image/png" {
+		return "png"
+	}
+	return "jpg"
+}
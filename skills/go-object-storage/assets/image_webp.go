@@ -0,0 +1,57 @@
+// internal/upload/infrastructure/imaging/webp.go
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// VP8X feature flags announcing metadata chunks.
+const (
+	vp8xFlagEXIF = 0x08
+	vp8xFlagXMP  = 0x04
+)
+
+// stripWebPMetadata drops the EXIF and XMP chunks from a WebP file and clears
+// their flags in the VP8X header. Image data chunks are copied untouched.
+//
+// Layout: "RIFF" size "WEBP", then chunks of fourcc, little-endian size and
+// payload padded to an even length.
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("%w: not a RIFF WebP file", ErrUnsupportedImage)
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:12])
+	for rest := data[12:]; len(rest) > 0; {
+		if len(rest) < 8 {
+			return nil, fmt.Errorf("%w: truncated WebP chunk header", ErrUnsupportedImage)
+		}
+		fourcc := string(rest[0:4])
+		size := int(binary.LittleEndian.Uint32(rest[4:8]))
+		end := 8 + size + size%2
+		if size < 0 || end > len(rest) {
+			return nil, fmt.Errorf("%w: WebP chunk %q overruns the file", ErrUnsupportedImage, fourcc)
+		}
+		chunk := rest[:end]
+		rest = rest[end:]
+
+		switch fourcc {
+		case "EXIF", "XMP ":
+			continue
+		case "VP8X":
+			if size < 1 {
+				return nil, fmt.Errorf("%w: empty VP8X chunk", ErrUnsupportedImage)
+			}
+			chunk = bytes.Clone(chunk)
+			chunk[8] &^= vp8xFlagEXIF | vp8xFlagXMP
+		}
+		out.Write(chunk)
+	}
+
+	stripped := out.Bytes()
+	binary.LittleEndian.PutUint32(stripped[4:8], uint32(len(stripped)-8))
+	return stripped, nil
+}
@@ -0,0 +1,61 @@
+// internal/shared/storage/keys.go
+package storage
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Concern is the first key segment and matches the bucket it lives in.
+type Concern string
+
+const (
+	ConcernUploads Concern = "uploads"
+	ConcernExports Concern = "exports"
+	ConcernBackups Concern = "backups"
+)
+
+// ObjectKey builds a key following {concern}/{service}/{date}/{filename}.
+func ObjectKey(concern Concern, service string, at time.Time, filename string) string {
+	return path.Join(string(concern), service, at.UTC().Format(time.DateOnly), filename)
+}
+
+// RunKey builds {concern}/{service}/{date}/{run}/{filename} for objects
+// written together by one job run, so a rerun on the same day never touches
+// the keys of an earlier run. run comes from NewRunID.
+func RunKey(concern Concern, service string, at time.Time, run, filename string) string {
+	return path.Join(string(concern), service, at.UTC().Format(time.DateOnly), run, filename)
+}
+
+// runIDPrefix marks a run segment, which lifecycle rules treat as part of
+// the version like {date}.
+const runIDPrefix = "run-"
+
+// NewRunID returns an ID that sorts by start time within a day, e.g.
+// run-093012-3f2a9c1e.
+func NewRunID(at time.Time) string {
+	return runIDPrefix + at.UTC().Format("150405") + "-" + uuid.NewString()[:8]
+}
+
+// UploadKey builds a collision-free key for client uploads, e.g.
+// uploads/caregiver/2025-01-15/photo-3f2a….jpg. The client-supplied filename
+// is never used — only its extension — so users can't pick or overwrite keys.
+func UploadKey(service, purpose, ext string, at time.Time) string {
+	ext = strings.ToLower(strings.TrimPrefix(ext, "."))
+	filename := fmt.Sprintf("%s-%s", purpose, uuid.NewString())
+	if ext != "" {
+		filename += "." + ext
+	}
+	return ObjectKey(ConcernUploads, service, at, filename)
+}
+
+// DerivedKey names an object generated from another one, next to it:
+// uploads/caregiver/2025-01-15/photo-3f2a.jpg → uploads/caregiver/2025-01-15/photo-3f2a_sm.jpg
+func DerivedKey(key, variant, ext string) string {
+	base := strings.TrimSuffix(key, path.Ext(key))
+	return base + "_" + variant + "." + strings.TrimPrefix(ext, ".")
+}
@@ -0,0 +1,115 @@
+// internal/shared/storage/kms.go
+package storage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// KeyManager wraps and unwraps data keys with key-encryption keys (KEKs) we
+// control. Implementations: LocalKeyManager (file), or adapters for AWS KMS,
+// GCP KMS, Vault Transit — the EncryptedStore doesn't care which.
+type KeyManager interface {
+	// CurrentKeyID is the KEK used to wrap new data keys.
+	CurrentKeyID() string
+	WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+var ErrUnknownKeyID = errors.New("storage: unknown key id")
+
+// LocalKeyManager keeps KEKs in a JSON file mounted from a secret:
+//
+//	{
+//	  "current": "2025-01",
+//	  "keys": {
+//	    "2025-01": "<base64 32 bytes>",
+//	    "2024-07": "<base64 32 bytes>"
+//	  }
+//	}
+//
+// Old keys stay in the file until every object has been re-wrapped.
+type LocalKeyManager struct {
+	current string
+	keks    map[string]cipher.AEAD
+}
+
+type localKeyFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+func NewLocalKeyManager(path string) (*LocalKeyManager, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	var file localKeyFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %w", err)
+	}
+
+	m := &LocalKeyManager{current: file.Current, keks: make(map[string]cipher.AEAD, len(file.Keys))}
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 base64-encoded bytes", id)
+		}
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		m.keks[id] = aead
+	}
+	if _, ok := m.keks[m.current]; !ok {
+		return nil, fmt.Errorf("current key %q not found in key file", m.current)
+	}
+	return m, nil
+}
+
+func (m *LocalKeyManager) CurrentKeyID() string { return m.current }
+
+// WrapKey returns nonce || AES-GCM(kek, dataKey), bound to keyID.
+func (m *LocalKeyManager) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	kek, ok := m.keks[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, keyID)
+	}
+	nonce := make([]byte, kek.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return kek.Seal(nonce, nonce, dataKey, []byte(keyID)), nil
+}
+
+func (m *LocalKeyManager) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	kek, ok := m.keks[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, keyID)
+	}
+	if len(wrapped) < kek.NonceSize() {
+		return nil, errors.New("wrapped key too short")
+	}
+	nonce, ciphertext := wrapped[:kek.NonceSize()], wrapped[kek.NonceSize():]
+	dataKey, err := kek.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
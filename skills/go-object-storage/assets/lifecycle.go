@@ -0,0 +1,331 @@
+// internal/shared/storage/lifecycle.go
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"api/booking/internal/shared/config"
+)
+
+// MetaLegalHold marks an object that no lifecycle rule may touch.
+const MetaLegalHold = "legal-hold"
+
+type LifecycleAction string
+
+const (
+	ActionExpire     LifecycleAction = "expire"
+	ActionTransition LifecycleAction = "transition" // Move to ColdPrefix
+	ActionHold       LifecycleAction = "hold"       // Would act, but legal hold is set
+)
+
+// LifecycleRule is declared in config so STORAGE_LIFECYCLE_RULES is parsed
+// with the rest of the environment.
+type LifecycleRule = config.LifecycleRule
+
+// DefaultLifecycleInterval is used when Start is given no interval.
+const DefaultLifecycleInterval = 24 * time.Hour
+
+func validateRule(r LifecycleRule) error {
+	switch {
+	case r.ID == "" || r.Bucket == "":
+		return errors.New("lifecycle rule requires id and bucket")
+	case r.ColdAfterDays > 0 && r.ColdPrefix == "":
+		return fmt.Errorf("rule %s: cold_after_days requires cold_prefix", r.ID)
+	}
+	return nil
+}
+
+type LifecycleDecision struct {
+	RuleID string          `json:"rule_id"`
+	Bucket string          `json:"bucket"`
+	Key    string          `json:"key"`
+	Action LifecycleAction `json:"action"`
+	Target string          `json:"target,omitempty"` // Destination key for transitions
+	Reason string          `json:"reason"`
+	Size   int64           `json:"size"`
+}
+
+type LifecycleReport struct {
+	DryRun    bool                `json:"dry_run"`
+	StartedAt time.Time           `json:"started_at"`
+	Decisions []LifecycleDecision `json:"decisions"`
+	Expired   int                 `json:"expired"`
+	Moved     int                 `json:"moved"`
+	Held      int                 `json:"held"`
+	Bytes     int64               `json:"bytes"` // Bytes expired or moved
+}
+
+// LifecycleRunner applies rules to any ObjectStore that also implements
+// HeadStore (legal holds) and Copier (cold moves), so it works the same on
+// S3, GCS and MinIO.
+type LifecycleRunner struct {
+	store ObjectStore
+	rules []LifecycleRule
+	now   func() time.Time
+}
+
+func NewLifecycleRunner(store ObjectStore, rules []LifecycleRule) (*LifecycleRunner, error) {
+	for _, r := range rules {
+		if err := validateRule(r); err != nil {
+			return nil, err
+		}
+	}
+	return &LifecycleRunner{store: store, rules: rules, now: time.Now}, nil
+}
+
+// Start runs the rules every interval until ctx is cancelled. An interval of
+// zero or less means DefaultLifecycleInterval.
+func (r *LifecycleRunner) Start(ctx context.Context, interval time.Duration, dryRun bool) {
+	if interval <= 0 {
+		interval = DefaultLifecycleInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := r.Run(ctx, dryRun)
+		if err != nil {
+			slog.ErrorContext(ctx, "lifecycle run failed", "error", err)
+		} else {
+			slog.InfoContext(ctx, "lifecycle run completed",
+				"dry_run", report.DryRun,
+				"expired", report.Expired,
+				"moved", report.Moved,
+				"held", report.Held,
+				"bytes", report.Bytes,
+			)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run evaluates every rule once. With dryRun the report lists what would
+// happen and nothing is modified.
+func (r *LifecycleRunner) Run(ctx context.Context, dryRun bool) (*LifecycleReport, error) {
+	report := &LifecycleReport{DryRun: dryRun, StartedAt: r.now()}
+	handled := make(map[string]bool) // bucket/key already decided by an earlier rule
+
+	for _, rule := range r.rules {
+		decisions, err := r.evaluate(ctx, rule, handled)
+		if err != nil {
+			return report, fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+		for _, d := range decisions {
+			if !dryRun {
+				if err := r.apply(ctx, d); err != nil {
+					return report, fmt.Errorf("rule %s: %w", rule.ID, err)
+				}
+			}
+			report.add(d)
+		}
+	}
+	return report, nil
+}
+
+func (r *LifecycleRunner) evaluate(ctx context.Context, rule LifecycleRule, handled map[string]bool) ([]LifecycleDecision, error) {
+	now := r.now()
+	var objects []ObjectInfo
+	for obj, err := range All(ctx, r.store, rule.Bucket, ListOptions{Prefix: rule.Prefix}) {
+		if err != nil {
+			return nil, err
+		}
+		if rule.ColdPrefix != "" && strings.HasPrefix(obj.Key, rule.ColdPrefix) {
+			continue
+		}
+		if !handled[rule.Bucket+"/"+obj.Key] {
+			objects = append(objects, obj)
+		}
+	}
+
+	superseded := supersededVersions(objects, rule.KeepLast)
+
+	var decisions []LifecycleDecision
+	for _, obj := range objects {
+		age := now.Sub(objectDate(obj))
+		d := LifecycleDecision{RuleID: rule.ID, Bucket: rule.Bucket, Key: obj.Key, Size: obj.Size}
+		switch {
+		case superseded[obj.Key]:
+			d.Action, d.Reason = ActionExpire, fmt.Sprintf("older than last %d versions", rule.KeepLast)
+		case rule.ExpireAfterDays > 0 && age > days(rule.ExpireAfterDays):
+			d.Action, d.Reason = ActionExpire, fmt.Sprintf("older than %d days", rule.ExpireAfterDays)
+		case rule.ColdAfterDays > 0 && age > days(rule.ColdAfterDays):
+			d.Action, d.Reason = ActionTransition, fmt.Sprintf("older than %d days", rule.ColdAfterDays)
+			d.Target = rule.ColdPrefix + obj.Key
+		default:
+			continue
+		}
+
+		// Only candidates pay for a HEAD to check the legal hold.
+		held, err := r.onLegalHold(ctx, rule.Bucket, obj.Key)
+		if err != nil {
+			return nil, err
+		}
+		if held {
+			d.Reason = fmt.Sprintf("legal hold (would %s: %s)", d.Action, d.Reason)
+			d.Action, d.Target = ActionHold, ""
+		}
+
+		handled[rule.Bucket+"/"+obj.Key] = true
+		decisions = append(decisions, d)
+	}
+	return decisions, nil
+}
+
+func (r *LifecycleRunner) apply(ctx context.Context, d LifecycleDecision) error {
+	switch d.Action {
+	case ActionExpire:
+		return r.store.Delete(ctx, d.Bucket, d.Key)
+	case ActionTransition:
+		if err := copyObject(ctx, r.store, d.Bucket, d.Key, d.Target, nil); err != nil {
+			return err
+		}
+		// The copy was conditional on the source's ETag, so this deletes the
+		// same version that was moved.
+		return r.store.Delete(ctx, d.Bucket, d.Key)
+	}
+	return nil
+}
+
+func (r *LifecycleRunner) onLegalHold(ctx context.Context, bucket, key string) (bool, error) {
+	info, err := Head(ctx, r.store, bucket, key)
+	if errors.Is(err, ErrObjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to head %s: %w", key, err)
+	}
+	return info.Metadata[MetaLegalHold] == "true", nil
+}
+
+// SetLegalHold sets or clears the legal hold flag. Object metadata is
+// immutable, so the object is copied onto itself server-side with the new
+// metadata; the body never passes through the API.
+func SetLegalHold(ctx context.Context, store ObjectStore, bucket, key string, hold bool) error {
+	return copyObject(ctx, store, bucket, key, key, func(meta map[string]string) {
+		if hold {
+			meta[MetaLegalHold] = "true"
+		} else {
+			delete(meta, MetaLegalHold)
+		}
+	})
+}
+
+// copyObject copies from to to with a server-side Copy, keeping the content
+// type and metadata (after edit). It fails with ErrPreconditionFailed if from
+// is replaced between the Head and the Copy.
+func copyObject(ctx context.Context, store ObjectStore, bucket, from, to string, edit func(map[string]string)) error {
+	copier, ok := store.(Copier)
+	if !ok {
+		return ErrCopyNotSupported
+	}
+	info, err := Head(ctx, store, bucket, from)
+	if err != nil {
+		return fmt.Errorf("failed to head %s: %w", from, err)
+	}
+
+	meta := maps.Clone(info.Metadata)
+	if meta == nil {
+		meta = make(map[string]string)
+	}
+	if edit != nil {
+		edit(meta)
+	}
+	err = copier.Copy(ctx, bucket, from, to, CopyOptions{
+		ContentType: info.ContentType,
+		Metadata:    meta,
+		IfMatch:     info.ETag,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy %s to %s: %w", from, to, err)
+	}
+	return nil
+}
+
+// supersededVersions returns keys beyond the newest keep versions per group.
+func supersededVersions(objects []ObjectInfo, keep int) map[string]bool {
+	superseded := make(map[string]bool)
+	if keep <= 0 {
+		return superseded
+	}
+
+	groups := make(map[string][]ObjectInfo)
+	for _, obj := range objects {
+		g := versionGroup(obj.Key)
+		groups[g] = append(groups[g], obj)
+	}
+	for _, versions := range groups {
+		// Newest first by the date in the key: LastModified moves whenever
+		// an object is rewritten, e.g. to set a legal hold. Run IDs sort by
+		// start time, so the key breaks ties between runs on one day.
+		sort.Slice(versions, func(i, j int) bool {
+			di, dj := objectDate(versions[i]), objectDate(versions[j])
+			if !di.Equal(dj) {
+				return di.After(dj)
+			}
+			return versions[i].Key > versions[j].Key
+		})
+		for _, old := range versions[min(keep, len(versions)):] {
+			superseded[old.Key] = true
+		}
+	}
+	return superseded
+}
+
+// versionGroup strips the {date} segment from {concern}/{service}/{date}/{filename},
+// and the {run} segment too from {concern}/{service}/{date}/{run}/{filename}.
+func versionGroup(key string) string {
+	parent, _, file, ok := splitDateKey(key)
+	if !ok {
+		return key
+	}
+	return parent + file
+}
+
+// objectDate is when obj was created: the {date} segment of its key when it
+// has one, so copies don't restart the clock, and LastModified otherwise.
+func objectDate(obj ObjectInfo) time.Time {
+	if _, date, _, ok := splitDateKey(obj.Key); ok {
+		return date
+	}
+	return obj.LastModified
+}
+
+func splitDateKey(key string) (parent string, date time.Time, file string, ok bool) {
+	dir, file := path.Split(key)
+	parent, segment := path.Split(strings.TrimSuffix(dir, "/"))
+	if strings.HasPrefix(segment, runIDPrefix) {
+		parent, segment = path.Split(strings.TrimSuffix(parent, "/"))
+	}
+	date, err := time.Parse(time.DateOnly, segment)
+	if err != nil {
+		return "", time.Time{}, "", false
+	}
+	return parent, date, file, true
+}
+
+func (rep *LifecycleReport) add(d LifecycleDecision) {
+	rep.Decisions = append(rep.Decisions, d)
+	switch d.Action {
+	case ActionExpire:
+		rep.Expired++
+		rep.Bytes += d.Size
+	case ActionTransition:
+		rep.Moved++
+		rep.Bytes += d.Size
+	case ActionHold:
+		rep.Held++
+	}
+}
+
+func days(n int) time.Duration { return time.Duration(n) * 24 * time.Hour }
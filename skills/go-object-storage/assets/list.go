@@ -0,0 +1,77 @@
+// internal/shared/storage/list.go
+package storage
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	maxListKeys         = 1000 // S3/MinIO/GCS page limit
+	metadataConcurrency = 8
+)
+
+// Lister is the subset of ObjectStore needed to walk a prefix page by page.
+type Lister interface {
+	ListPage(ctx context.Context, bucket string, opts ListOptions) (*ListResult, error)
+}
+
+// All iterates every object matching opts, fetching pages lazily. Iteration
+// stops at the first error, which is yielded with a zero ObjectInfo.
+//
+//	for obj, err := range storage.All(ctx, store, bucket, storage.ListOptions{Prefix: "exports/"}) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+func All(ctx context.Context, store Lister, bucket string, opts ListOptions) iter.Seq2[ObjectInfo, error] {
+	return func(yield func(ObjectInfo, error) bool) {
+		for {
+			page, err := store.ListPage(ctx, bucket, opts)
+			if err != nil {
+				yield(ObjectInfo{}, err)
+				return
+			}
+			for _, obj := range page.Objects {
+				if !yield(obj, nil) {
+					return
+				}
+			}
+			if page.NextToken == "" {
+				return
+			}
+			opts.Token = page.NextToken
+		}
+	}
+}
+
+func (o ListOptions) pageSize() int32 {
+	if o.MaxKeys <= 0 || o.MaxKeys > maxListKeys {
+		return maxListKeys
+	}
+	return o.MaxKeys
+}
+
+// fillMetadata HEADs objects concurrently to populate ContentType and Metadata,
+// which list APIs don't return.
+func fillMetadata(ctx context.Context, store HeadStore, bucket string, objects []ObjectInfo) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(metadataConcurrency)
+
+	for i := range objects {
+		g.Go(func() error {
+			info, err := store.Head(gctx, bucket, objects[i].Key)
+			if err != nil {
+				return fmt.Errorf("failed to head %s: %w", objects[i].Key, err)
+			}
+			objects[i].ContentType = info.ContentType
+			objects[i].Metadata = info.Metadata
+			return nil
+		})
+	}
+	return g.Wait()
+}
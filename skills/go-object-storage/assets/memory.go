@@ -0,0 +1,208 @@
+// internal/shared/storage/memory.go
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryObject struct {
+	data         []byte
+	contentType  string
+	metadata     map[string]string
+	lastModified time.Time
+	etag         string
+}
+
+// InMemoryStore implements ObjectStore for tests and local tools. Keys are
+// listed in lexical order, like S3.
+type InMemoryStore struct {
+	mu      sync.RWMutex
+	objects map[string]*memoryObject // bucket/key → object
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{objects: make(map[string]*memoryObject)}
+}
+
+func (s *InMemoryStore) Put(ctx context.Context, bucket, key string, reader io.Reader, opts PutOptions) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	sum := md5.Sum(data)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[bucket+"/"+key] = &memoryObject{
+		data:         data,
+		contentType:  opts.ContentType,
+		metadata:     maps.Clone(opts.Metadata),
+		lastModified: time.Now(),
+		etag:         fmt.Sprintf("%q", hex.EncodeToString(sum[:])),
+	}
+	return nil
+}
+
+func (s *InMemoryStore) Get(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.objects[bucket+"/"+key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+	}
+	return io.NopCloser(bytes.NewReader(obj.data)), nil
+}
+
+func (s *InMemoryStore) GetWithInfo(ctx context.Context, bucket, key string) (io.ReadCloser, *ObjectInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.objects[bucket+"/"+key]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+	}
+	info := obj.info(key)
+	return io.NopCloser(bytes.NewReader(obj.data)), &info, nil
+}
+
+// Copy shares the source bytes; stored data is never mutated in place.
+func (s *InMemoryStore) Copy(ctx context.Context, bucket, from, to string, opts CopyOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src, ok := s.objects[bucket+"/"+from]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrObjectNotFound, from)
+	}
+	if opts.IfMatch != "" && opts.IfMatch != src.etag {
+		return fmt.Errorf("%w: %s", ErrPreconditionFailed, from)
+	}
+	s.objects[bucket+"/"+to] = &memoryObject{
+		data:         src.data,
+		contentType:  opts.ContentType,
+		metadata:     maps.Clone(opts.Metadata),
+		lastModified: time.Now(),
+		etag:         src.etag,
+	}
+	return nil
+}
+
+func (s *InMemoryStore) Delete(ctx context.Context, bucket, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, bucket+"/"+key)
+	return nil
+}
+
+func (s *InMemoryStore) Exists(ctx context.Context, bucket, key string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.objects[bucket+"/"+key]
+	return ok, nil
+}
+
+func (s *InMemoryStore) Head(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.objects[bucket+"/"+key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+	}
+	info := obj.info(key)
+	return &info, nil
+}
+
+func (s *InMemoryStore) SignedURL(ctx context.Context, bucket, key string, expiry time.Duration) (string, error) {
+	return "memory://" + bucket + "/" + key, nil
+}
+
+func (s *InMemoryStore) List(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	for obj, err := range All(ctx, s, bucket, ListOptions{Prefix: prefix}) {
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// ListPage pages by key: NextToken is the last key returned.
+func (s *InMemoryStore) ListPage(ctx context.Context, bucket string, opts ListOptions) (*ListResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	after := opts.StartAfter
+	if opts.Token != "" {
+		after = opts.Token
+	}
+
+	var keys []string
+	for k := range s.objects {
+		key, ok := strings.CutPrefix(k, bucket+"/")
+		if ok && strings.HasPrefix(key, opts.Prefix) && key > after {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	result := &ListResult{}
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		entry := ""
+		if opts.Delimiter != "" {
+			rest := strings.TrimPrefix(key, opts.Prefix)
+			if i := strings.Index(rest, opts.Delimiter); i >= 0 {
+				entry = opts.Prefix + rest[:i+len(opts.Delimiter)]
+			}
+		}
+		if entry != "" && seen[entry] {
+			continue
+		}
+		if len(result.Objects)+len(result.Prefixes) == int(opts.pageSize()) {
+			result.NextToken = key
+			break
+		}
+		if entry != "" {
+			seen[entry] = true
+			result.Prefixes = append(result.Prefixes, entry)
+			continue
+		}
+		result.Objects = append(result.Objects, s.objects[bucket+"/"+key].info(key))
+	}
+	if result.NextToken != "" {
+		// Resume after the last entry actually returned.
+		result.NextToken = lastListed(result)
+	}
+	return result, nil
+}
+
+func (o *memoryObject) info(key string) ObjectInfo {
+	return ObjectInfo{
+		Key:          key,
+		Size:         int64(len(o.data)),
+		LastModified: o.lastModified,
+		ContentType:  o.contentType,
+		Metadata:     maps.Clone(o.metadata),
+		ETag:         o.etag,
+	}
+}
+
+func lastListed(r *ListResult) string {
+	last := ""
+	if n := len(r.Objects); n > 0 {
+		last = r.Objects[n-1].Key
+	}
+	if n := len(r.Prefixes); n > 0 && r.Prefixes[n-1] > last {
+		// Skip every key under the prefix: "\xff" sorts after any UTF-8 byte.
+		last = r.Prefixes[n-1] + "\xff"
+	}
+	return last
+}
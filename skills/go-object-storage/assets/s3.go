@@ -3,14 +3,17 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 )
 
 type S3Store struct {
@@ -37,14 +40,11 @@ func NewS3Store(ctx context.Context, endpoint string, region string, forcePathSt
 	}, nil
 }
 
+// Put streams reader of unknown length. Small objects go out in a single
+// PutObject; anything larger than one part switches to multipart upload.
+// See s3_multipart.go.
 func (s *S3Store) Put(ctx context.Context, bucket, key string, reader io.Reader, opts PutOptions) error {
-	input := &s3.PutObjectInput{
-		Bucket:      aws.String(bucket),
-		Key:         aws.String(key),
-		Body:        reader,
-		ContentType: aws.String(opts.ContentType),
-	}
-	_, err := s.client.PutObject(ctx, input)
+	_, err := s.PutMultipart(ctx, bucket, key, reader, MultipartOptions{PutOptions: opts})
 	return err
 }
 
@@ -82,6 +82,80 @@ func (s *S3Store) Exists(ctx context.Context, bucket, key string) (bool, error)
 	return true, nil
 }
 
+func (s *S3Store) Head(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var nsk *types.NotFound
+		if errors.As(err, &nsk) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return &ObjectInfo{
+		Key:          key,
+		Size:         aws.ToInt64(output.ContentLength),
+		LastModified: aws.ToTime(output.LastModified),
+		ContentType:  aws.ToString(output.ContentType),
+		Metadata:     output.Metadata,
+		ETag:         aws.ToString(output.ETag),
+	}, nil
+}
+
+func (s *S3Store) GetWithInfo(ctx context.Context, bucket, key string) (io.ReadCloser, *ObjectInfo, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var nsk *types.NoSuchKey
+		if errors.As(err, &nsk) {
+			return nil, nil, ErrObjectNotFound
+		}
+		return nil, nil, err
+	}
+	return output.Body, &ObjectInfo{
+		Key:          key,
+		Size:         aws.ToInt64(output.ContentLength),
+		LastModified: aws.ToTime(output.LastModified),
+		ContentType:  aws.ToString(output.ContentType),
+		Metadata:     output.Metadata,
+		ETag:         aws.ToString(output.ETag),
+	}, nil
+}
+
+// Copy uses CopyObject with the metadata replaced, so it is limited to
+// objects of 5 GiB or less.
+func (s *S3Store) Copy(ctx context.Context, bucket, from, to string, opts CopyOptions) error {
+	input := &s3.CopyObjectInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(to),
+		CopySource:        aws.String(url.PathEscape(bucket + "/" + from)),
+		MetadataDirective: types.MetadataDirectiveReplace,
+		ContentType:       aws.String(opts.ContentType),
+		Metadata:          opts.Metadata,
+	}
+	if opts.IfMatch != "" {
+		input.CopySourceIfMatch = aws.String(opts.IfMatch)
+	}
+
+	_, err := s.client.CopyObject(ctx, input)
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "PreconditionFailed" {
+			return fmt.Errorf("%w: %s", ErrPreconditionFailed, from)
+		}
+		var nsk *types.NoSuchKey
+		if errors.As(err, &nsk) {
+			return fmt.Errorf("%w: %s", ErrObjectNotFound, from)
+		}
+		return fmt.Errorf("failed to copy %s to %s: %w", from, to, err)
+	}
+	return nil
+}
+
 func (s *S3Store) SignedURL(ctx context.Context, bucket, key string, expiry time.Duration) (string, error) {
 	req, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
@@ -93,21 +167,59 @@ func (s *S3Store) SignedURL(ctx context.Context, bucket, key string, expiry time
 	return req.URL, nil
 }
 
+// List returns every object under prefix, following continuation tokens.
+// Prefer All or ListPage for large prefixes to avoid holding everything in memory.
 func (s *S3Store) List(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error) {
-	output, err := s.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
-		Bucket: aws.String(bucket),
-		Prefix: aws.String(prefix),
-	})
+	var objects []ObjectInfo
+	for obj, err := range All(ctx, s, bucket, ListOptions{Prefix: prefix}) {
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+func (s *S3Store) ListPage(ctx context.Context, bucket string, opts ListOptions) (*ListResult, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucket),
+		Prefix:  aws.String(opts.Prefix),
+		MaxKeys: aws.Int32(opts.pageSize()),
+	}
+	if opts.Delimiter != "" {
+		input.Delimiter = aws.String(opts.Delimiter)
+	}
+	if opts.Token != "" {
+		input.ContinuationToken = aws.String(opts.Token)
+	} else if opts.StartAfter != "" {
+		input.StartAfter = aws.String(opts.StartAfter)
+	}
+
+	output, err := s.client.ListObjectsV2(ctx, input)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to list %s/%s: %w", bucket, opts.Prefix, err)
 	}
-	var objects []ObjectInfo
+
+	result := &ListResult{Objects: make([]ObjectInfo, 0, len(output.Contents))}
 	for _, obj := range output.Contents {
-		objects = append(objects, ObjectInfo{
-			Key:          *obj.Key,
-			Size:         *obj.Size,
-			LastModified: *obj.LastModified,
+		result.Objects = append(result.Objects, ObjectInfo{
+			Key:          aws.ToString(obj.Key),
+			Size:         aws.ToInt64(obj.Size),
+			LastModified: aws.ToTime(obj.LastModified),
+			ETag:         aws.ToString(obj.ETag),
 		})
 	}
-	return objects, nil
+	for _, p := range output.CommonPrefixes {
+		result.Prefixes = append(result.Prefixes, aws.ToString(p.Prefix))
+	}
+	if aws.ToBool(output.IsTruncated) {
+		result.NextToken = aws.ToString(output.NextContinuationToken)
+	}
+
+	if opts.WithMetadata {
+		if err := fillMetadata(ctx, s, bucket, result.Objects); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
@@ -0,0 +1,373 @@
+// internal/shared/storage/s3_multipart.go
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	DefaultPartSize    = 16 << 20 // 16 MiB
+	MinPartSize        = 5 << 20  // S3 minimum for every part except the last
+	DefaultConcurrency = 4
+	maxParts           = 10000 // S3 hard limit
+	minPartBuffer      = 64 << 10
+)
+
+func (o MultipartOptions) withDefaults() MultipartOptions {
+	if o.Resume != nil && o.Resume.PartSize > 0 {
+		o.PartSize = o.Resume.PartSize // Part boundaries must match the original upload
+	}
+	if o.PartSize == 0 {
+		o.PartSize = DefaultPartSize
+	}
+	if o.PartSize < MinPartSize {
+		o.PartSize = MinPartSize
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultConcurrency
+	}
+	return o
+}
+
+// PutMultipart streams reader to bucket/key without knowing its length.
+// Objects smaller than one part are sent with a single PutObject; larger ones
+// use a multipart upload with opts.Concurrency parts in flight.
+//
+// Cancelling ctx aborts the upload and deletes uploaded parts. Any other
+// failure returns a *ResumableError whose State can be passed back in
+// opts.Resume together with a reader positioned at the start of the object.
+func (s *S3Store) PutMultipart(ctx context.Context, bucket, key string, reader io.Reader, opts MultipartOptions) (*UploadResult, error) {
+	opts = opts.withDefaults()
+	hasher := newContentHasher()
+	body := io.TeeReader(reader, hasher)
+
+	first, err := readPart(body, opts.PartSize)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Resume == nil && int64(len(first)) < opts.PartSize {
+		return s.putSingle(ctx, bucket, key, first, hasher, opts)
+	}
+
+	state, uploaded, err := s.startOrResume(ctx, bucket, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &multipartUpload{client: s.client, state: state, opts: opts, uploaded: uploaded}
+	parts, err := u.run(ctx, first, body)
+	if err != nil {
+		return nil, s.failMultipart(ctx, state, err)
+	}
+
+	sha, md := hasher.sums()
+	if err := verifyChecksums(sha, md, opts); err != nil {
+		_ = s.AbortMultipart(context.WithoutCancel(ctx), state)
+		return nil, err
+	}
+
+	_, err = s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(state.UploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return nil, s.failMultipart(ctx, state, fmt.Errorf("failed to complete upload: %w", err))
+	}
+
+	return &UploadResult{Key: key, Size: hasher.size, Parts: len(parts), SHA256: sha, MD5: md}, nil
+}
+
+func (s *S3Store) AbortMultipart(ctx context.Context, state UploadState) error {
+	_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(state.Bucket),
+		Key:      aws.String(state.Key),
+		UploadId: aws.String(state.UploadID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to abort upload %s: %w", state.UploadID, err)
+	}
+	return nil
+}
+
+func (s *S3Store) putSingle(ctx context.Context, bucket, key string, data []byte, hasher *contentHasher, opts MultipartOptions) (*UploadResult, error) {
+	sha, md := hasher.sums()
+	if err := verifyChecksums(sha, md, opts); err != nil {
+		return nil, err
+	}
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(key),
+		Body:              bytes.NewReader(data),
+		ContentLength:     aws.Int64(int64(len(data))),
+		ContentType:       aws.String(opts.ContentType),
+		Metadata:          opts.Metadata,
+		ContentMD5:        aws.String(base64Hex(md)),
+		ChecksumAlgorithm: types.ChecksumAlgorithmSha256,
+		ChecksumSHA256:    aws.String(base64Hex(sha)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to put object: %w", err)
+	}
+
+	if opts.Progress != nil {
+		opts.Progress(UploadProgress{BytesUploaded: int64(len(data)), TotalBytes: opts.Size, PartsCompleted: 1})
+	}
+	return &UploadResult{Key: key, Size: int64(len(data)), Parts: 1, SHA256: sha, MD5: md}, nil
+}
+
+// startOrResume creates a new multipart upload, or lists the parts S3 already
+// holds for opts.Resume so they can be skipped.
+func (s *S3Store) startOrResume(ctx context.Context, bucket, key string, opts MultipartOptions) (UploadState, map[int32]types.Part, error) {
+	if opts.Resume != nil {
+		uploaded, err := s.listParts(ctx, *opts.Resume)
+		if err != nil {
+			return UploadState{}, nil, err
+		}
+		return *opts.Resume, uploaded, nil
+	}
+
+	out, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(key),
+		ContentType:       aws.String(opts.ContentType),
+		Metadata:          opts.Metadata,
+		ChecksumAlgorithm: types.ChecksumAlgorithmSha256,
+	})
+	if err != nil {
+		return UploadState{}, nil, fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	state := UploadState{Bucket: bucket, Key: key, UploadID: aws.ToString(out.UploadId), PartSize: opts.PartSize}
+	return state, map[int32]types.Part{}, nil
+}
+
+func (s *S3Store) listParts(ctx context.Context, state UploadState) (map[int32]types.Part, error) {
+	parts := make(map[int32]types.Part)
+	paginator := s3.NewListPartsPaginator(s.client, &s3.ListPartsInput{
+		Bucket:   aws.String(state.Bucket),
+		Key:      aws.String(state.Key),
+		UploadId: aws.String(state.UploadID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list parts of upload %s: %w", state.UploadID, err)
+		}
+		for _, p := range page.Parts {
+			parts[aws.ToInt32(p.PartNumber)] = p
+		}
+	}
+	return parts, nil
+}
+
+func (s *S3Store) failMultipart(ctx context.Context, state UploadState, err error) error {
+	if ctx.Err() == nil {
+		return &ResumableError{State: state, Err: err}
+	}
+
+	// The caller gave up — don't leave orphaned parts billed in the bucket.
+	abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	if abortErr := s.AbortMultipart(abortCtx, state); abortErr != nil {
+		slog.WarnContext(ctx, "failed to abort multipart upload",
+			"error", abortErr,
+			"bucket", state.Bucket,
+			"key", state.Key,
+			"upload_id", state.UploadID,
+		)
+	}
+	return fmt.Errorf("multipart upload aborted: %w", ctx.Err())
+}
+
+type multipartUpload struct {
+	client   *s3.Client
+	state    UploadState
+	opts     MultipartOptions
+	uploaded map[int32]types.Part // Parts already on S3 when resuming
+
+	mu       sync.Mutex
+	progress UploadProgress
+}
+
+// run reads parts sequentially and uploads up to opts.Concurrency of them in
+// parallel. At most Concurrency+1 part buffers are held in memory.
+func (u *multipartUpload) run(ctx context.Context, first []byte, body io.Reader) ([]types.CompletedPart, error) {
+	g, gctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, u.opts.Concurrency)
+
+	var (
+		mu    sync.Mutex
+		parts []types.CompletedPart
+	)
+
+	next := first
+	for number := int32(1); next != nil; number++ {
+		if number > maxParts {
+			_ = g.Wait()
+			return nil, fmt.Errorf("object exceeds %d parts, increase PartSize", maxParts)
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-gctx.Done():
+			if err := g.Wait(); err != nil {
+				return nil, err
+			}
+			return nil, ctx.Err()
+		}
+
+		data := next
+		g.Go(func() error {
+			defer func() { <-sem }()
+			part, err := u.uploadPart(gctx, number, data)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			parts = append(parts, part)
+			mu.Unlock()
+			return nil
+		})
+
+		var err error
+		if next, err = readPart(body, u.opts.PartSize); err != nil {
+			_ = g.Wait()
+			return nil, err
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return aws.ToInt32(parts[i].PartNumber) < aws.ToInt32(parts[j].PartNumber)
+	})
+	return parts, nil
+}
+
+func (u *multipartUpload) uploadPart(ctx context.Context, number int32, data []byte) (types.CompletedPart, error) {
+	md := md5.Sum(data)
+	sha := sha256.Sum256(data)
+	shaB64 := base64.StdEncoding.EncodeToString(sha[:])
+
+	// Resuming: skip parts S3 already holds with identical content.
+	if done, ok := u.uploaded[number]; ok && aws.ToString(done.ChecksumSHA256) == shaB64 {
+		u.report(int64(len(data)))
+		return types.CompletedPart{PartNumber: aws.Int32(number), ETag: done.ETag, ChecksumSHA256: done.ChecksumSHA256}, nil
+	}
+
+	out, err := u.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:            aws.String(u.state.Bucket),
+		Key:               aws.String(u.state.Key),
+		UploadId:          aws.String(u.state.UploadID),
+		PartNumber:        aws.Int32(number),
+		Body:              bytes.NewReader(data),
+		ContentLength:     aws.Int64(int64(len(data))),
+		ContentMD5:        aws.String(base64.StdEncoding.EncodeToString(md[:])), // S3 rejects corrupted parts
+		ChecksumAlgorithm: types.ChecksumAlgorithmSha256,
+		ChecksumSHA256:    aws.String(shaB64),
+	})
+	if err != nil {
+		return types.CompletedPart{}, fmt.Errorf("failed to upload part %d: %w", number, err)
+	}
+
+	u.report(int64(len(data)))
+	return types.CompletedPart{PartNumber: aws.Int32(number), ETag: out.ETag, ChecksumSHA256: out.ChecksumSHA256}, nil
+}
+
+func (u *multipartUpload) report(n int64) {
+	if u.opts.Progress == nil {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.progress.BytesUploaded += n
+	u.progress.TotalBytes = u.opts.Size
+	u.progress.PartsCompleted++
+	u.opts.Progress(u.progress)
+}
+
+// readPart reads up to size bytes. It returns nil, nil at end of stream.
+// The buffer grows with the data instead of starting at size, so a small
+// object headed for putSingle never costs a full part in memory.
+func readPart(r io.Reader, size int64) ([]byte, error) {
+	buf := make([]byte, 0, min(size, minPartBuffer))
+	for int64(len(buf)) < size {
+		if len(buf) == cap(buf) {
+			grown := make([]byte, len(buf), min(2*int64(cap(buf)), size))
+			copy(grown, buf)
+			buf = grown
+		}
+		n, err := r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read part: %w", err)
+		}
+	}
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	return buf, nil
+}
+
+// contentHasher computes whole-object digests while the body streams through.
+type contentHasher struct {
+	sha  hash.Hash
+	md5  hash.Hash
+	size int64
+}
+
+func newContentHasher() *contentHasher {
+	return &contentHasher{sha: sha256.New(), md5: md5.New()}
+}
+
+func (h *contentHasher) Write(p []byte) (int, error) {
+	h.sha.Write(p)
+	h.md5.Write(p)
+	h.size += int64(len(p))
+	return len(p), nil
+}
+
+func (h *contentHasher) sums() (sha, md string) {
+	return hex.EncodeToString(h.sha.Sum(nil)), hex.EncodeToString(h.md5.Sum(nil))
+}
+
+func verifyChecksums(sha, md string, opts MultipartOptions) error {
+	if opts.ExpectedSHA256 != "" && opts.ExpectedSHA256 != sha {
+		return fmt.Errorf("%w: sha256 expected %s, got %s", ErrChecksumMismatch, opts.ExpectedSHA256, sha)
+	}
+	if opts.ExpectedMD5 != "" && opts.ExpectedMD5 != md {
+		return fmt.Errorf("%w: md5 expected %s, got %s", ErrChecksumMismatch, opts.ExpectedMD5, md)
+	}
+	return nil
+}
+
+func base64Hex(h string) string {
+	raw, _ := hex.DecodeString(h)
+	return base64.StdEncoding.EncodeToString(raw)
+}
@@ -0,0 +1,100 @@
+// internal/shared/storage/s3_presign.go
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const defaultUploadExpiry = 15 * time.Minute
+
+// SignedPutURL presigns a PUT. Content-Type and Content-Length are part of the
+// signature, so the client must upload exactly opts.Size bytes of that type.
+func (s *S3Store) SignedPutURL(ctx context.Context, bucket, key string, opts SignedUploadOptions) (*SignedUpload, error) {
+	if opts.Size <= 0 || (opts.MaxSize > 0 && opts.Size > opts.MaxSize) {
+		return nil, fmt.Errorf("invalid upload size %d (max %d)", opts.Size, opts.MaxSize)
+	}
+	expiry := uploadExpiry(opts)
+
+	req, err := s.presign.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(opts.ContentType),
+		ContentLength: aws.Int64(opts.Size),
+		Metadata:      opts.Metadata,
+	}, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign put: %w", err)
+	}
+
+	headers := make(map[string]string, len(req.SignedHeader))
+	for name := range req.SignedHeader {
+		if name == "Host" {
+			continue
+		}
+		headers[name] = req.SignedHeader.Get(name)
+	}
+
+	return &SignedUpload{
+		Method:    http.MethodPut,
+		URL:       req.URL,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(expiry),
+	}, nil
+}
+
+// SignedPostPolicy presigns a browser-style multipart/form-data POST. Unlike
+// PUT, the policy enforces a size range, so the client doesn't need to know the
+// exact size up front.
+func (s *S3Store) SignedPostPolicy(ctx context.Context, bucket, key string, opts SignedUploadOptions) (*SignedUpload, error) {
+	if opts.MaxSize <= 0 {
+		return nil, fmt.Errorf("post policy requires MaxSize")
+	}
+	expiry := uploadExpiry(opts)
+
+	conditions := []any{
+		[]any{"content-length-range", 1, opts.MaxSize},
+		[]any{"eq", "$Content-Type", opts.ContentType},
+	}
+	for k, v := range opts.Metadata {
+		conditions = append(conditions, []any{"eq", "$x-amz-meta-" + k, v})
+	}
+
+	req, err := s.presign.PresignPostObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(opts.ContentType),
+		Metadata:    opts.Metadata,
+	}, func(o *s3.PresignPostOptions) {
+		o.Expires = expiry
+		o.Conditions = conditions
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign post policy: %w", err)
+	}
+
+	fields := req.Values
+	fields["Content-Type"] = opts.ContentType
+	for k, v := range opts.Metadata {
+		fields["x-amz-meta-"+k] = v
+	}
+
+	return &SignedUpload{
+		Method:    http.MethodPost,
+		URL:       req.URL,
+		Fields:    fields,
+		ExpiresAt: time.Now().Add(expiry),
+	}, nil
+}
+
+func uploadExpiry(opts SignedUploadOptions) time.Duration {
+	if opts.Expiry > 0 {
+		return opts.Expiry
+	}
+	return defaultUploadExpiry
+}
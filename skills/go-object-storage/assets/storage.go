@@ -3,6 +3,8 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"time"
 )
@@ -14,6 +16,45 @@ type ObjectStore interface {
 	Exists(ctx context.Context, bucket, key string) (bool, error)
 	SignedURL(ctx context.Context, bucket, key string, expiry time.Duration) (string, error)
 	List(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error)
+	ListPage(ctx context.Context, bucket string, opts ListOptions) (*ListResult, error)
+}
+
+// MultipartStore is implemented by stores that can upload large or
+// unknown-length objects in parts. Callers type-assert for it:
+//
+//	if mp, ok := store.(storage.MultipartStore); ok { ... }
+type MultipartStore interface {
+	PutMultipart(ctx context.Context, bucket, key string, reader io.Reader, opts MultipartOptions) (*UploadResult, error)
+	AbortMultipart(ctx context.Context, state UploadState) error
+}
+
+// HeadStore is implemented by stores that can read an object's size, type
+// and metadata without fetching the body. Call it through Head.
+type HeadStore interface {
+	Head(ctx context.Context, bucket, key string) (*ObjectInfo, error)
+}
+
+// ObjectReader is implemented by stores that return an object's info from
+// the same GET that streams its body, so the metadata always describes the
+// bytes being read — a Head followed by a Get can straddle an overwrite.
+type ObjectReader interface {
+	GetWithInfo(ctx context.Context, bucket, key string) (io.ReadCloser, *ObjectInfo, error)
+}
+
+// Copier copies objects within a bucket server-side; the body never passes
+// through the API. Copying a key onto itself with new Metadata rewrites only
+// its metadata.
+type Copier interface {
+	Copy(ctx context.Context, bucket, from, to string, opts CopyOptions) error
+}
+
+// UploadSigner lets clients upload directly to the bucket without routing
+// bytes through the API.
+type UploadSigner interface {
+	// SignedPutURL presigns a PUT for an object of exactly opts.Size bytes.
+	SignedPutURL(ctx context.Context, bucket, key string, opts SignedUploadOptions) (*SignedUpload, error)
+	// SignedPostPolicy presigns a browser form POST accepting 1..opts.MaxSize bytes.
+	SignedPostPolicy(ctx context.Context, bucket, key string, opts SignedUploadOptions) (*SignedUpload, error)
 }
 
 type PutOptions struct {
@@ -26,4 +67,109 @@ type ObjectInfo struct {
 	Size         int64
 	LastModified time.Time
 	ContentType  string
+	Metadata     map[string]string // Filled by Head
+	ETag         string            // Changes whenever the object is rewritten
+}
+
+type CopyOptions struct {
+	ContentType string
+	Metadata    map[string]string // Replaces the source's metadata
+	IfMatch     string            // Source ETag; ErrPreconditionFailed if it has changed
+}
+
+type ListOptions struct {
+	Prefix       string
+	Delimiter    string // "/" groups keys into "directories" returned as Prefixes
+	StartAfter   string // Key to start listing after (first page only)
+	MaxKeys      int32  // Page size; default and maximum 1000
+	Token        string // NextToken from the previous page
+	WithMetadata bool   // Fill ContentType/Metadata — costs one HEAD per object
+}
+
+type ListResult struct {
+	Objects   []ObjectInfo
+	Prefixes  []string // Common prefixes when Delimiter is set
+	NextToken string   // Empty on the last page
+}
+
+type SignedUploadOptions struct {
+	ContentType string
+	Size        int64 // Exact size for PUT; ignored by POST
+	MaxSize     int64 // Upper bound enforced by the POST policy
+	Metadata    map[string]string
+	Expiry      time.Duration
+}
+
+type SignedUpload struct {
+	Method    string            `json:"method"` // "PUT" or "POST"
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers,omitempty"` // PUT: headers the client must send
+	Fields    map[string]string `json:"fields,omitempty"`  // POST: form fields sent before the file
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+type MultipartOptions struct {
+	PutOptions
+	PartSize       int64        // Bytes per part; default 16 MiB, minimum 5 MiB
+	Concurrency    int          // Parts uploaded in parallel; default 4
+	Size           int64        // Total size if known — only used for progress
+	Resume         *UploadState // Continue a previous upload instead of starting a new one
+	ExpectedSHA256 string       // Hex digest of the whole object; aborts on mismatch
+	ExpectedMD5    string       // Hex digest of the whole object; aborts on mismatch
+	Progress       func(UploadProgress)
+}
+
+type UploadProgress struct {
+	BytesUploaded  int64
+	TotalBytes     int64 // 0 when unknown
+	PartsCompleted int
+}
+
+// UploadState identifies an in-flight multipart upload. Persist it to resume
+// after a crash or network failure.
+type UploadState struct {
+	Bucket   string `json:"bucket"`
+	Key      string `json:"key"`
+	UploadID string `json:"upload_id"`
+	PartSize int64  `json:"part_size"`
+}
+
+type UploadResult struct {
+	Key    string
+	Size   int64
+	Parts  int
+	SHA256 string // Hex digest computed while streaming
+	MD5    string // Hex digest computed while streaming
+}
+
+var (
+	ErrObjectNotFound     = errors.New("storage: object not found")
+	ErrChecksumMismatch   = errors.New("storage: checksum mismatch")
+	ErrHeadNotSupported   = errors.New("storage: store cannot read object metadata")
+	ErrCopyNotSupported   = errors.New("storage: store cannot copy objects server-side")
+	ErrPreconditionFailed = errors.New("storage: object changed since it was read")
+)
+
+// Head returns bucket/key's info if store implements HeadStore, and
+// ErrHeadNotSupported otherwise.
+func Head(ctx context.Context, store ObjectStore, bucket, key string) (*ObjectInfo, error) {
+	h, ok := store.(HeadStore)
+	if !ok {
+		return nil, ErrHeadNotSupported
+	}
+	return h.Head(ctx, bucket, key)
 }
+
+// ResumableError is returned when a multipart upload fails for a reason other
+// than cancellation. Uploaded parts are kept so State can be passed back via
+// MultipartOptions.Resume.
+type ResumableError struct {
+	State UploadState
+	Err   error
+}
+
+func (e *ResumableError) Error() string {
+	return fmt.Sprintf("multipart upload %s interrupted: %v", e.State.UploadID, e.Err)
+}
+
+func (e *ResumableError) Unwrap() error { return e.Err }
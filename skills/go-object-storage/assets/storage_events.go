@@ -0,0 +1,117 @@
+// internal/upload/infrastructure/storageevents/store.go
+package storageevents
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"api/caregiver/internal/shared/storage"
+	"api/caregiver/internal/upload/domain"
+	"github.com/google/uuid"
+)
+
+// NotifyingStore is an ObjectStore decorator that publishes
+// storage.object.created / storage.object.deleted after each successful Put
+// and Delete, so consumers subscribe instead of polling List.
+//
+// Publishing is best-effort: the object is already written, so a publish
+// failure is logged and the call still succeeds.
+type NotifyingStore struct {
+	storage.ObjectStore
+	events domain.EventPublisher
+}
+
+func NewNotifyingStore(inner storage.ObjectStore, events domain.EventPublisher) *NotifyingStore {
+	return &NotifyingStore{ObjectStore: inner, events: events}
+}
+
+func (s *NotifyingStore) Put(ctx context.Context, bucket, key string, reader io.Reader, opts storage.PutOptions) error {
+	counter := &countingReader{r: reader}
+	if err := s.ObjectStore.Put(ctx, bucket, key, counter, opts); err != nil {
+		return err
+	}
+	s.publish(ctx, domain.EventObjectCreated, domain.ObjectEvent{
+		Bucket:      bucket,
+		Key:         key,
+		Size:        counter.n,
+		ContentType: opts.ContentType,
+		Source:      domain.ObjectEventSourceAPI,
+	})
+	return nil
+}
+
+func (s *NotifyingStore) Delete(ctx context.Context, bucket, key string) error {
+	if err := s.ObjectStore.Delete(ctx, bucket, key); err != nil {
+		return err
+	}
+	s.publish(ctx, domain.EventObjectDeleted, domain.ObjectEvent{
+		Bucket: bucket,
+		Key:    key,
+		Source: domain.ObjectEventSourceAPI,
+	})
+	return nil
+}
+
+// Head passes through to the inner store; embedding ObjectStore alone would
+// hide it from storage.Head.
+func (s *NotifyingStore) Head(ctx context.Context, bucket, key string) (*storage.ObjectInfo, error) {
+	return storage.Head(ctx, s.ObjectStore, bucket, key)
+}
+
+func (s *NotifyingStore) GetWithInfo(ctx context.Context, bucket, key string) (io.ReadCloser, *storage.ObjectInfo, error) {
+	reader, ok := s.ObjectStore.(storage.ObjectReader)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T does not implement storage.ObjectReader", s.ObjectStore)
+	}
+	return reader.GetWithInfo(ctx, bucket, key)
+}
+
+// Copy publishes storage.object.created for the destination, as S3 does for
+// ObjectCreated:Copy.
+func (s *NotifyingStore) Copy(ctx context.Context, bucket, from, to string, opts storage.CopyOptions) error {
+	copier, ok := s.ObjectStore.(storage.Copier)
+	if !ok {
+		return storage.ErrCopyNotSupported
+	}
+	if err := copier.Copy(ctx, bucket, from, to, opts); err != nil {
+		return err
+	}
+	s.publish(ctx, domain.EventObjectCreated, domain.ObjectEvent{
+		Bucket:      bucket,
+		Key:         to,
+		ContentType: opts.ContentType,
+		Source:      domain.ObjectEventSourceAPI,
+	})
+	return nil
+}
+
+func (s *NotifyingStore) publish(ctx context.Context, topic string, data domain.ObjectEvent) {
+	err := s.events.Publish(ctx, topic, domain.Event{
+		ID:        uuid.NewString(),
+		Type:      topic,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to publish storage event",
+			"error", err,
+			"topic", topic,
+			"bucket", data.Bucket,
+			"key", data.Key,
+		)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
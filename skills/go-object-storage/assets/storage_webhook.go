@@ -0,0 +1,134 @@
+// internal/upload/infrastructure/storageevents/webhook.go
+package storageevents
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"api/caregiver/internal/shared/server"
+	"api/caregiver/internal/upload/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler receives S3-format bucket notifications — MinIO's webhook
+// target, or AWS S3 events forwarded by an EventBridge API destination — and
+// republishes them as storage.object.* events. Use it for buckets written
+// directly by clients (presigned uploads), where NotifyingStore sees nothing.
+type WebhookHandler struct {
+	events domain.EventPublisher
+	token  string // Shared secret sent as "Authorization: Bearer {token}"
+}
+
+func NewWebhookHandler(events domain.EventPublisher, token string) *WebhookHandler {
+	return &WebhookHandler{events: events, token: token}
+}
+
+// RegisterRoutes mounts the receiver outside user auth: providers
+// authenticate with the shared token instead of a user JWT.
+func (h *WebhookHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/webhooks/storage", h.Receive)
+}
+
+type bucketNotification struct {
+	Records []bucketRecord `json:"Records"`
+}
+
+type bucketRecord struct {
+	EventName string    `json:"eventName"` // AWS "ObjectCreated:Put", MinIO "s3:ObjectCreated:Put"
+	EventTime time.Time `json:"eventTime"`
+	S3        struct {
+		Bucket struct {
+			Name string `json:"name"`
+		} `json:"bucket"`
+		Object struct {
+			Key         string `json:"key"` // URL-encoded
+			Size        int64  `json:"size"`
+			ETag        string `json:"eTag"`
+			ContentType string `json:"contentType"` // MinIO only
+			Sequencer   string `json:"sequencer"`
+		} `json:"object"`
+	} `json:"s3"`
+}
+
+func (h *WebhookHandler) Receive(c *gin.Context) {
+	if !h.authorized(c.GetHeader("Authorization")) {
+		server.Fail(c, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid webhook token")
+		return
+	}
+
+	var n bucketNotification
+	if err := c.ShouldBindJSON(&n); err != nil {
+		server.Fail(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid notification body")
+		return
+	}
+
+	ctx := c.Request.Context()
+	for _, r := range n.Records {
+		event, ok := toEvent(r)
+		if !ok {
+			continue // Test events, replication, restores, ...
+		}
+		if err := h.events.Publish(ctx, event.Type, event); err != nil {
+			// A non-2xx makes the provider retry the whole batch; consumers
+			// dedupe on the deterministic event ID.
+			slog.ErrorContext(ctx, "failed to publish bucket notification",
+				"error", err,
+				"event_name", r.EventName,
+				"bucket", r.S3.Bucket.Name,
+			)
+			server.Fail(c, http.StatusServiceUnavailable, "PUBLISH_FAILED", "Failed to publish event")
+			return
+		}
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) authorized(header string) bool {
+	if h.token == "" {
+		return false // Refuse everything rather than run an open endpoint
+	}
+	return subtle.ConstantTimeCompare([]byte(header), []byte("Bearer "+h.token)) == 1
+}
+
+func toEvent(r bucketRecord) (domain.Event, bool) {
+	name := strings.TrimPrefix(r.EventName, "s3:")
+	var topic string
+	switch {
+	case strings.HasPrefix(name, "ObjectCreated:"):
+		topic = domain.EventObjectCreated
+	case strings.HasPrefix(name, "ObjectRemoved:"):
+		topic = domain.EventObjectDeleted
+	default:
+		return domain.Event{}, false
+	}
+
+	key, err := url.QueryUnescape(r.S3.Object.Key)
+	if err != nil {
+		key = r.S3.Object.Key
+	}
+	bucket := r.S3.Bucket.Name
+
+	// Providers deliver at least once; the same notification always maps to
+	// the same ID.
+	id := uuid.NewSHA1(uuid.NameSpaceURL, []byte(bucket+"/"+key+"#"+name+"#"+r.S3.Object.Sequencer))
+
+	return domain.Event{
+		ID:        id.String(),
+		Type:      topic,
+		Timestamp: r.EventTime,
+		Data: domain.ObjectEvent{
+			Bucket:      bucket,
+			Key:         key,
+			Size:        r.S3.Object.Size,
+			ContentType: r.S3.Object.ContentType,
+			ETag:        strings.Trim(r.S3.Object.ETag, `"`),
+			Source:      domain.ObjectEventSourceBucket,
+		},
+	}, true
+}
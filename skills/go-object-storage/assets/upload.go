@@ -0,0 +1,130 @@
+// internal/upload/domain/upload.go
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UploadStatus string
+
+const (
+	UploadPending   UploadStatus = "pending"
+	UploadConfirmed UploadStatus = "confirmed"
+	UploadRejected  UploadStatus = "rejected" // Failed processing; the object was deleted
+)
+
+// Upload is a slot reserved for a direct-to-bucket client upload. It is
+// created pending and becomes confirmed once the object is verified in storage
+// and processed, or rejected if processing finds it invalid.
+type Upload struct {
+	ID          string
+	OwnerID     string
+	Purpose     string // e.g. "pet_photo", "caregiver_document"
+	Bucket      string
+	Key         string
+	ContentType string
+	MaxSize     int64
+	Size        int64 // Actual size, set on confirmation
+	Status      UploadStatus
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+	ConfirmedAt time.Time
+}
+
+func NewUpload(ownerID, purpose, bucket, key, contentType string, maxSize int64, expiresAt, now time.Time) (*Upload, error) {
+	if ownerID == "" {
+		return nil, ErrUploadOwnerRequired
+	}
+	if maxSize <= 0 {
+		return nil, validationError("max size must be positive")
+	}
+	return &Upload{
+		ID:          uuid.NewString(),
+		OwnerID:     ownerID,
+		Purpose:     purpose,
+		Bucket:      bucket,
+		Key:         key,
+		ContentType: contentType,
+		MaxSize:     maxSize,
+		Status:      UploadPending,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   now,
+	}, nil
+}
+
+// ObjectMetadata tags the stored object with its slot. Confirm checks
+// upload-id, so anything rewriting the object must keep these.
+func (u *Upload) ObjectMetadata() map[string]string {
+	return map[string]string{"upload-id": u.ID, "owner-id": u.OwnerID}
+}
+
+// Confirm checks the stored object against what the slot allowed.
+func (u *Upload) Confirm(size int64, contentType string, now time.Time) error {
+	switch u.Status {
+	case UploadConfirmed:
+		return ErrUploadAlreadyConfirmed
+	case UploadRejected:
+		return ErrUploadRejected
+	}
+	if now.After(u.ExpiresAt) {
+		return ErrUploadExpired
+	}
+	if size <= 0 || size > u.MaxSize {
+		return validationError(fmt.Sprintf("object size %d exceeds limit %d", size, u.MaxSize))
+	}
+	if contentType != u.ContentType {
+		return validationError(fmt.Sprintf("content type %q does not match %q", contentType, u.ContentType))
+	}
+	u.Size = size
+	u.Status = UploadConfirmed
+	u.ConfirmedAt = now
+	return nil
+}
+
+func (u *Upload) Reject() {
+	u.Status = UploadRejected
+}
+
+type UploadRepository interface {
+	Save(ctx context.Context, upload *Upload) error
+	FindByID(ctx context.Context, id string) (*Upload, error)
+}
+
+// Errors implement the apperror marker interfaces; HTTP and gRPC adapters
+// map them through apperror.From without the domain importing it.
+var (
+	ErrUploadNotFound           = notFoundError("upload not found")
+	ErrUploadForbidden          = forbiddenError("upload belongs to another user")
+	ErrUploadAlreadyConfirmed   = conflictError("upload already confirmed")
+	ErrUploadExpired            = validationError("upload slot expired")
+	ErrUploadObjectMissing      = validationError("object was not uploaded")
+	ErrUploadOwnerRequired      = validationError("owner is required")
+	ErrUploadPurposeInvalid     = validationError("unknown upload purpose")
+	ErrUploadContentTypeInvalid = validationError("content type not allowed for this purpose")
+	ErrUploadSizeInvalid        = validationError("size is missing or exceeds the limit for this purpose")
+	ErrUploadRejected           = validationError("uploaded file is not a valid file of its type")
+)
+
+type notFoundError string
+
+func (e notFoundError) Error() string { return string(e) }
+func (notFoundError) NotFound()       {}
+
+type forbiddenError string
+
+func (e forbiddenError) Error() string { return string(e) }
+func (forbiddenError) Forbidden()      {}
+
+type conflictError string
+
+func (e conflictError) Error() string { return string(e) }
+func (conflictError) Conflict()       {}
+
+type validationError string
+
+func (e validationError) Error() string { return string(e) }
+func (validationError) Validation()     {}
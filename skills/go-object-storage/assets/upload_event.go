@@ -0,0 +1,52 @@
+// internal/upload/domain/event.go
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	EventImageProcessed = "uploads.image.processed"
+	EventObjectCreated  = "storage.object.created"
+	EventObjectDeleted  = "storage.object.deleted"
+)
+
+// Where an object event was observed.
+const (
+	ObjectEventSourceAPI    = "api"    // Put/Delete through NotifyingStore
+	ObjectEventSourceBucket = "bucket" // Provider bucket notification
+)
+
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data"`
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, topic string, event Event) error
+}
+
+type ImageProcessedEvent struct {
+	UploadID    string            `json:"upload_id"`
+	OwnerID     string            `json:"owner_id"`
+	Bucket      string            `json:"bucket"`
+	Key         string            `json:"key"`
+	ContentType string            `json:"content_type"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Thumbnails  map[string]string `json:"thumbnails"` // size name → key
+}
+
+// ObjectEvent is the payload of storage.object.created and
+// storage.object.deleted. Size and ContentType are zero for deletes.
+type ObjectEvent struct {
+	Bucket      string `json:"bucket"`
+	Key         string `json:"key"`
+	Size        int64  `json:"size,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	ETag        string `json:"etag,omitempty"`
+	Source      string `json:"source"`
+}
@@ -0,0 +1,73 @@
+// internal/upload/infrastructure/handler/http.go
+package handler
+
+import (
+	"net/http"
+
+	"api/caregiver/internal/shared/server"
+	"api/caregiver/internal/upload/application"
+	"github.com/gin-gonic/gin"
+)
+
+type UploadHandler struct {
+	service *application.UploadService
+}
+
+func NewUploadHandler(service *application.UploadService) *UploadHandler {
+	return &UploadHandler{service: service}
+}
+
+// RegisterRoutes mounts the two-step direct upload flow:
+//
+//	POST /uploads              → signed PUT URL or POST policy
+//	(client uploads straight to the bucket)
+//	POST /uploads/:id/confirm  → verify object and record it
+func (h *UploadHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	uploads := rg.Group("/uploads")
+	{
+		uploads.POST("", h.RequestSlot)
+		uploads.POST("/:id/confirm", h.Confirm)
+	}
+}
+
+type RequestSlotRequest struct {
+	Purpose     string `json:"purpose" binding:"required,oneof=pet_photo caregiver_document"`
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"omitempty,min=1"`
+	Method      string `json:"method" binding:"omitempty,oneof=PUT POST"`
+}
+
+func (h *UploadHandler) RequestSlot(c *gin.Context) {
+	var req RequestSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		server.FailWithDetails(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body", server.ValidationDetails(c, err))
+		return
+	}
+
+	output, err := h.service.RequestSlot(c.Request.Context(), application.RequestSlotInput{
+		OwnerID:     c.GetString("user_id"),
+		Purpose:     req.Purpose,
+		ContentType: req.ContentType,
+		Size:        req.Size,
+		Method:      req.Method,
+	})
+	if err != nil {
+		server.HandleDomainError(c, err)
+		return
+	}
+
+	server.OK(c, http.StatusCreated, output)
+}
+
+func (h *UploadHandler) Confirm(c *gin.Context) {
+	output, err := h.service.Confirm(c.Request.Context(), application.ConfirmUploadInput{
+		OwnerID:  c.GetString("user_id"),
+		UploadID: c.Param("id"),
+	})
+	if err != nil {
+		server.HandleDomainError(c, err)
+		return
+	}
+
+	server.OK(c, http.StatusOK, output)
+}
@@ -0,0 +1,228 @@
+// internal/upload/application/service.go
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"api/caregiver/internal/shared/storage"
+	"api/caregiver/internal/upload/domain"
+)
+
+// UploadPolicy constrains what a client may upload for a given purpose.
+type UploadPolicy struct {
+	ContentTypes []string
+	MaxSize      int64
+}
+
+var DefaultUploadPolicies = map[string]UploadPolicy{
+	"pet_photo":          {ContentTypes: []string{"image/jpeg", "image/png", "image/webp"}, MaxSize: 10 << 20},
+	"caregiver_document": {ContentTypes: []string{"application/pdf", "image/jpeg", "image/png"}, MaxSize: 20 << 20},
+}
+
+// UploadProcessor runs while an upload is confirmed, e.g. the image pipeline.
+// Errors wrapping domain.ErrUploadRejected mark the upload rejected; any
+// other error fails the confirmation so the client can retry it.
+type UploadProcessor interface {
+	Process(ctx context.Context, upload *domain.Upload) error
+}
+
+type UploadService struct {
+	repo       domain.UploadRepository
+	store      storage.ObjectStore
+	signer     storage.UploadSigner
+	bucket     string // BucketConfig.Uploads
+	service    string // Second key segment, e.g. "caregiver"
+	policies   map[string]UploadPolicy
+	expiry     time.Duration
+	now        func() time.Time
+	processors []UploadProcessor
+}
+
+func NewUploadService(
+	repo domain.UploadRepository,
+	store storage.ObjectStore,
+	signer storage.UploadSigner,
+	bucket, service string,
+	policies map[string]UploadPolicy,
+) *UploadService {
+	return &UploadService{
+		repo:     repo,
+		store:    store,
+		signer:   signer,
+		bucket:   bucket,
+		service:  service,
+		policies: policies,
+		expiry:   15 * time.Minute,
+		now:      time.Now,
+	}
+}
+
+type RequestSlotInput struct {
+	OwnerID     string
+	Purpose     string
+	ContentType string
+	Size        int64  // Required for PUT
+	Method      string // "PUT" (mobile) or "POST" (browser form)
+}
+
+type RequestSlotOutput struct {
+	UploadID string                `json:"upload_id"`
+	Key      string                `json:"key"`
+	Upload   *storage.SignedUpload `json:"upload"`
+}
+
+func (s *UploadService) RequestSlot(ctx context.Context, input RequestSlotInput) (*RequestSlotOutput, error) {
+	policy, err := s.policyFor(input.Purpose, input.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	if input.Method != "POST" && (input.Size <= 0 || input.Size > policy.MaxSize) {
+		return nil, domain.ErrUploadSizeInvalid
+	}
+
+	now := s.now()
+	key := storage.UploadKey(s.service, input.Purpose, extensionFor(input.ContentType), now)
+	upload, err := domain.NewUpload(input.OwnerID, input.Purpose, s.bucket, key, input.ContentType, policy.MaxSize, now.Add(s.expiry), now)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := storage.SignedUploadOptions{
+		ContentType: input.ContentType,
+		Size:        input.Size,
+		MaxSize:     policy.MaxSize,
+		Metadata:    upload.ObjectMetadata(),
+		Expiry:      s.expiry,
+	}
+
+	var signed *storage.SignedUpload
+	if input.Method == "POST" {
+		signed, err = s.signer.SignedPostPolicy(ctx, s.bucket, key, opts)
+	} else {
+		signed, err = s.signer.SignedPutURL(ctx, s.bucket, key, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign upload: %w", err)
+	}
+
+	if err := s.repo.Save(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	return &RequestSlotOutput{UploadID: upload.ID, Key: key, Upload: signed}, nil
+}
+
+type ConfirmUploadInput struct {
+	OwnerID  string
+	UploadID string
+}
+
+type ConfirmUploadOutput struct {
+	UploadID string `json:"upload_id"`
+	Key      string `json:"key"`
+	Size     int64  `json:"size"`
+}
+
+// Confirm verifies the object actually landed in the bucket with the promised
+// type and size before the upload can be referenced by other aggregates.
+func (s *UploadService) Confirm(ctx context.Context, input ConfirmUploadInput) (*ConfirmUploadOutput, error) {
+	upload, err := s.repo.FindByID(ctx, input.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.OwnerID != input.OwnerID {
+		return nil, domain.ErrUploadForbidden
+	}
+
+	info, err := storage.Head(ctx, s.store, upload.Bucket, upload.Key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, domain.ErrUploadObjectMissing
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect uploaded object: %w", err)
+	}
+	if info.Metadata["upload-id"] != upload.ID {
+		return nil, domain.ErrUploadObjectMissing
+	}
+
+	if err := upload.Confirm(info.Size, info.ContentType, s.now()); err != nil {
+		if errors.Is(err, domain.ErrUploadAlreadyConfirmed) {
+			return nil, err
+		}
+		// Reject the object so it can't be referenced later.
+		if delErr := s.store.Delete(ctx, upload.Bucket, upload.Key); delErr != nil {
+			slog.WarnContext(ctx, "failed to delete rejected upload", "error", delErr, "upload_id", upload.ID)
+		}
+		return nil, err
+	}
+
+	// Processing (EXIF/GPS stripping) must finish before the upload is
+	// recorded as confirmed, or a failure would leave the original exposed.
+	if err := s.process(ctx, upload); err != nil {
+		if !errors.Is(err, domain.ErrUploadRejected) {
+			return nil, fmt.Errorf("failed to process upload %s: %w", upload.ID, err)
+		}
+		upload.Reject()
+		if saveErr := s.repo.Save(ctx, upload); saveErr != nil {
+			return nil, saveErr
+		}
+		return nil, domain.ErrUploadRejected
+	}
+
+	if err := s.repo.Save(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	return &ConfirmUploadOutput{UploadID: upload.ID, Key: upload.Key, Size: upload.Size}, nil
+}
+
+// OnConfirmed registers a processor that runs synchronously inside Confirm,
+// before the upload is saved as confirmed. Processors must be idempotent: a
+// failed confirmation is retried from the start.
+func (s *UploadService) OnConfirmed(p UploadProcessor) {
+	s.processors = append(s.processors, p)
+}
+
+func (s *UploadService) process(ctx context.Context, upload *domain.Upload) error {
+	for _, p := range s.processors {
+		if err := p.Process(ctx, upload); err != nil {
+			slog.WarnContext(ctx, "upload processing failed",
+				"error", err,
+				"upload_id", upload.ID,
+				"key", upload.Key,
+			)
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *UploadService) policyFor(purpose, contentType string) (UploadPolicy, error) {
+	policy, ok := s.policies[purpose]
+	if !ok {
+		return UploadPolicy{}, domain.ErrUploadPurposeInvalid
+	}
+	for _, ct := range policy.ContentTypes {
+		if ct == contentType {
+			return policy, nil
+		}
+	}
+	return UploadPolicy{}, domain.ErrUploadContentTypeInvalid
+}
+
+// uploadExtensions pins the key extension per content type. The system MIME
+// table can't be used: on many hosts image/jpeg maps to .jfif first.
+var uploadExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+func extensionFor(contentType string) string {
+	return uploadExtensions[contentType]
+}
@@ -0,0 +1,75 @@
+// internal/shared/middleware/metrics.go
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"api/booking/internal/shared/observability"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Metrics records RED metrics for every request: http_server_requests_total,
+// http_server_request_duration_seconds and http_server_active_requests on
+// /metrics. They're labelled by route template (/bookings/:id), never the
+// raw path, so series don't grow with IDs; unmatched paths share one label.
+func Metrics() gin.HandlerFunc {
+	meter := otel.Meter("api/booking/http")
+	requests, _ := meter.Int64Counter("http.server.requests",
+		metric.WithDescription("HTTP requests served"),
+		metric.WithUnit("{request}"),
+	)
+	duration, _ := meter.Float64Histogram("http.server.request.duration",
+		metric.WithDescription("Time to serve HTTP requests"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(observability.DurationBuckets...),
+	)
+	active, _ := meter.Int64UpDownCounter("http.server.active_requests",
+		metric.WithDescription("HTTP requests in flight"),
+		metric.WithUnit("{request}"),
+	)
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		base := []attribute.KeyValue{
+			attribute.String("http.request.method", httpMethod(c.Request.Method)),
+			attribute.String("http.route", route),
+		}
+
+		active.Add(ctx, 1, metric.WithAttributes(base...))
+		start := time.Now()
+		// Deferred so a panicking handler is still counted: gin.Recovery
+		// runs outside this middleware and writes the 500 after we return.
+		completed := false
+		defer func() {
+			status := c.Writer.Status()
+			if !completed && !c.Writer.Written() {
+				status = http.StatusInternalServerError
+			}
+			active.Add(ctx, -1, metric.WithAttributes(base...))
+			attrs := metric.WithAttributes(append(base, attribute.Int("http.response.status_code", status))...)
+			requests.Add(ctx, 1, attrs)
+			duration.Record(ctx, time.Since(start).Seconds(), attrs)
+		}()
+
+		c.Next()
+		completed = true
+	}
+}
+
+// httpMethod folds non-standard methods into one label value.
+func httpMethod(m string) string {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return m
+	}
+	return "_OTHER"
+}
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"time"
 
+	"api/booking/internal/shared/observability"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
@@ -14,7 +15,21 @@ func Tracing(serviceName string) gin.HandlerFunc {
 	return otelgin.Middleware(serviceName)
 }
 
-// RequestLogger logs every HTTP request with trace correlation
+// RequestID keeps the caller's X-Request-ID, or generates one, and stores it
+// in the request context so logs, outgoing gRPC calls, published events and
+// error bodies carry it. Register it first.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := observability.RequestIDOrNew(c.GetHeader(observability.RequestIDHeader))
+		c.Set("request_id", id)
+		c.Header(observability.RequestIDHeader, id)
+		c.Request = c.Request.WithContext(observability.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// RequestLogger logs every HTTP request; request_id, trace_id and span_id
+// come from observability.ContextHandler
 func RequestLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
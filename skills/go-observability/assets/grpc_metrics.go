@@ -0,0 +1,132 @@
+// internal/shared/observability/grpc_metrics.go
+package observability
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// rpcMetrics are the RED metrics for one side of a call: on /metrics,
+// rpc_server_calls_total, rpc_server_call_duration_seconds and
+// rpc_server_active_calls (rpc_client_* for clients), labelled by service,
+// method and status code.
+type rpcMetrics struct {
+	calls    metric.Int64Counter
+	duration metric.Float64Histogram
+	active   metric.Int64UpDownCounter
+}
+
+func newRPCMetrics(side string) *rpcMetrics {
+	meter := otel.Meter("api/booking/grpc")
+	calls, _ := meter.Int64Counter("rpc."+side+".calls",
+		metric.WithDescription("gRPC calls completed"),
+		metric.WithUnit("{call}"),
+	)
+	duration, _ := meter.Float64Histogram("rpc."+side+".call.duration",
+		metric.WithDescription("gRPC call duration"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(DurationBuckets...),
+	)
+	active, _ := meter.Int64UpDownCounter("rpc."+side+".active_calls",
+		metric.WithDescription("gRPC calls in flight"),
+		metric.WithUnit("{call}"),
+	)
+	return &rpcMetrics{calls: calls, duration: duration, active: active}
+}
+
+// start counts a call as in flight; the returned func records its outcome.
+func (m *rpcMetrics) start(ctx context.Context, fullMethod string) func(error) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	base := []attribute.KeyValue{
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", method),
+	}
+	m.active.Add(ctx, 1, metric.WithAttributes(base...))
+	begin := time.Now()
+
+	return func(err error) {
+		m.active.Add(ctx, -1, metric.WithAttributes(base...))
+		attrs := metric.WithAttributes(append(base, attribute.Int("rpc.grpc.status_code", int(status.Code(err))))...)
+		m.calls.Add(ctx, 1, attrs)
+		m.duration.Record(ctx, time.Since(begin).Seconds(), attrs)
+	}
+}
+
+func UnaryServerMetrics() grpc.UnaryServerInterceptor {
+	m := newRPCMetrics("server")
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		done := m.start(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(err)
+		return resp, err
+	}
+}
+
+// StreamServerMetrics measures the whole stream, from open to the
+// handler's return.
+func StreamServerMetrics() grpc.StreamServerInterceptor {
+	m := newRPCMetrics("server")
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		done := m.start(ss.Context(), info.FullMethod)
+		err := handler(srv, ss)
+		done(err)
+		return err
+	}
+}
+
+func UnaryClientMetrics() grpc.UnaryClientInterceptor {
+	m := newRPCMetrics("client")
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		done := m.start(ctx, method)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		done(err)
+		return err
+	}
+}
+
+// StreamClientMetrics records a client stream when RecvMsg returns io.EOF
+// or an error. A stream the caller abandons without reading to the end
+// stays counted as active.
+func StreamClientMetrics() grpc.StreamClientInterceptor {
+	m := newRPCMetrics("client")
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		done := m.start(ctx, method)
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			done(err)
+			return nil, err
+		}
+		return &metricsClientStream{ClientStream: cs, done: done}, nil
+	}
+}
+
+type metricsClientStream struct {
+	grpc.ClientStream
+	done func(error)
+	once sync.Once
+}
+
+func (s *metricsClientStream) RecvMsg(msg any) error {
+	err := s.ClientStream.RecvMsg(msg)
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				s.done(nil)
+				return
+			}
+			s.done(err)
+		})
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+// internal/shared/observability/grpc.go
+package observability
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryServerRequestID takes the caller's x-request-id (or starts a new
+// one), puts it in the context and echoes it in the response header, errors
+// included.
+func UnaryServerRequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = incomingRequestID(ctx)
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerRequestID() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &requestIDStream{ServerStream: ss, ctx: incomingRequestID(ss.Context())})
+	}
+}
+
+// UnaryClientRequestID forwards the request ID in ctx to the called service.
+func UnaryClientRequestID() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingRequestID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func StreamClientRequestID() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingRequestID(ctx), desc, cc, method, opts...)
+	}
+}
+
+func incomingRequestID(ctx context.Context) context.Context {
+	var incoming string
+	if values := metadata.ValueFromIncomingContext(ctx, RequestIDMetadataKey); len(values) > 0 {
+		incoming = values[0]
+	}
+	id := RequestIDOrNew(incoming)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, id))
+	return WithRequestID(ctx, id)
+}
+
+func outgoingRequestID(ctx context.Context) context.Context {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return metadata.AppendToOutgoingContext(ctx, RequestIDMetadataKey, id)
+	}
+	return ctx
+}
+
+type requestIDStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestIDStream) Context() context.Context { return s.ctx }
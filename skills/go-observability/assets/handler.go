@@ -0,0 +1,228 @@
+// internal/shared/observability/handler.go
+package observability
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Redacted replaces the value of sensitive attributes.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactKeys are always redacted. Keys are compared word by word,
+// case-insensitively, with words split at "_", "-", "." and camelCase: a
+// key matches when it contains one of these as whole words, so "Password",
+// "refresh_token", "password_hash", "ownerEmail" and "x-api-key" are all
+// caught but "route" is not.
+var DefaultRedactKeys = []string{"password", "token", "secret", "authorization", "api_key", "email", "rut"}
+
+// Sensitive marks a value that must never be logged whatever its key, e.g.
+// slog.Any("contact", observability.Sensitive(phone)). Domain types with
+// personal data implement slog.LogValuer instead and return only safe
+// fields; the handler resolves them before redacting by key.
+type Sensitive string
+
+func (Sensitive) LogValue() slog.Value { return slog.StringValue(Redacted) }
+
+// DebugSampling limits repeated debug records per message: in every Tick
+// the first First records with the same message are logged, then every
+// Thereafter-th (none when Thereafter is 0).
+type DebugSampling struct {
+	Tick       time.Duration
+	First      int
+	Thereafter int
+}
+
+type HandlerOptions struct {
+	RedactKeys    []string       // Added to DefaultRedactKeys
+	DebugSampling *DebugSampling // nil logs every debug record
+}
+
+// ContextHandler wraps the JSON/Text handler: it adds request_id, trace_id
+// and span_id from the context, redacts sensitive attributes and samples
+// debug logs. The level stays with the wrapped handler, so a *slog.LevelVar
+// in its options can be changed at runtime (see loglevel.AdminHandler).
+type ContextHandler struct {
+	next    slog.Handler
+	redact  [][]string // Words of each redact key
+	sampler *sampler
+	// scopes are the groups opened with WithGroup and the attributes added
+	// inside them. They are applied per record instead of on next, so the
+	// context IDs stay at the top level whatever group the caller is in.
+	scopes []scope
+}
+
+type scope struct {
+	group string
+	attrs []slog.Attr
+}
+
+func NewContextHandler(next slog.Handler, opts *HandlerOptions) *ContextHandler {
+	if opts == nil {
+		opts = &HandlerOptions{}
+	}
+	h := &ContextHandler{next: next}
+	for _, keys := range [][]string{DefaultRedactKeys, opts.RedactKeys} {
+		for _, k := range keys {
+			h.redact = append(h.redact, words(k))
+		}
+	}
+	if opts.DebugSampling != nil {
+		h.sampler = &sampler{cfg: *opts.DebugSampling, counts: make(map[string]*sampleCount)}
+	}
+	return h
+}
+
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level <= slog.LevelDebug && h.sampler != nil && !h.sampler.allow(r.Message, r.Time) {
+		return nil
+	}
+
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, h.redactAttr(a))
+		return true
+	})
+	for i := len(h.scopes) - 1; i >= 0; i-- {
+		attrs = append(slices.Clip(h.scopes[i].attrs), attrs...)
+		if len(attrs) > 0 { // slog drops empty groups
+			attrs = []slog.Attr{{Key: h.scopes[i].group, Value: slog.GroupValue(attrs...)}}
+		}
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	out.AddAttrs(attrs...)
+	if id := RequestIDFromContext(ctx); id != "" {
+		out.AddAttrs(slog.String("request_id", id))
+	}
+	if id := TraceIDFromContext(ctx); id != "" {
+		out.AddAttrs(slog.String("trace_id", id), slog.String("span_id", SpanIDFromContext(ctx)))
+	}
+	return h.next.Handle(ctx, out)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(a)
+	}
+	c := *h
+	if len(h.scopes) == 0 {
+		// Outside any group next can preformat them.
+		c.next = h.next.WithAttrs(redacted)
+		return &c
+	}
+	c.scopes = slices.Clone(h.scopes)
+	last := &c.scopes[len(c.scopes)-1]
+	last.attrs = append(slices.Clip(last.attrs), redacted...)
+	return &c
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.scopes = append(slices.Clip(h.scopes), scope{group: name})
+	return &c
+}
+
+func (h *ContextHandler) redactAttr(a slog.Attr) slog.Attr {
+	if h.sensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			redacted[i] = h.redactAttr(ga)
+		}
+		a.Value = slog.GroupValue(redacted...)
+	}
+	return a
+}
+
+func (h *ContextHandler) sensitive(key string) bool {
+	kw := words(key)
+	for _, rw := range h.redact {
+		for i := 0; i+len(rw) <= len(kw); i++ {
+			if slices.Equal(kw[i:i+len(rw)], rw) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// words splits a key into lower-case words at separators and at camelCase
+// boundaries: "ownerEmail" and "owner-email" are both [owner email].
+func words(key string) []string {
+	var out []string
+	start := -1
+	var prev rune
+	for i, r := range key {
+		sep := r == '_' || r == '-' || r == '.' || r == ' '
+		if start >= 0 && (sep || unicode.IsUpper(r) && unicode.IsLower(prev)) {
+			out = append(out, strings.ToLower(key[start:i]))
+			start = -1
+		}
+		if !sep && start < 0 {
+			start = i
+		}
+		prev = r
+	}
+	if start >= 0 {
+		out = append(out, strings.ToLower(key[start:]))
+	}
+	return out
+}
+
+// maxSampledMessages bounds the sampler's memory if messages are built
+// dynamically (they shouldn't be — put variable parts in attributes).
+const maxSampledMessages = 1000
+
+type sampler struct {
+	cfg    DebugSampling
+	mu     sync.Mutex
+	counts map[string]*sampleCount
+}
+
+type sampleCount struct {
+	start time.Time
+	n     int
+}
+
+func (s *sampler) allow(msg string, now time.Time) bool {
+	if now.IsZero() {
+		now = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counts[msg]
+	if !ok || now.Sub(c.start) >= s.cfg.Tick {
+		if !ok && len(s.counts) >= maxSampledMessages {
+			clear(s.counts)
+		}
+		c = &sampleCount{start: now}
+		s.counts[msg] = c
+	}
+	c.n++
+	if c.n <= s.cfg.First {
+		return true
+	}
+	return s.cfg.Thereafter > 0 && (c.n-s.cfg.First)%s.cfg.Thereafter == 0
+}
@@ -0,0 +1,243 @@
+// internal/shared/observability/handler_test.go
+package observability_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"api/booking/internal/shared/auth"
+	"api/booking/internal/shared/observability"
+	"api/booking/internal/shared/observability/loglevel"
+	"github.com/gin-gonic/gin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func newLogger(opts *observability.HandlerOptions) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return slog.New(observability.NewContextHandler(h, opts)), &buf
+}
+
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+type owner struct {
+	ID    string
+	Email string
+}
+
+func (o owner) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("id", o.ID), slog.String("email", o.Email))
+}
+
+func TestRedaction(t *testing.T) {
+	logger, buf := newLogger(&observability.HandlerOptions{RedactKeys: []string{"phone"}})
+	logger.With("api_key", "bst_abc").Info("login",
+		"user_id", "u-1",
+		"route", "/login",
+		"Password", "hunter2",
+		"password_hash", "$argon2id$...",
+		"refresh_token", "rt-1",
+		"token_hash", "9f86d0...",
+		"secret_hash", "2c26b4...",
+		"ownerEmail", "ana@example.cl",
+		"x-api-key", "bst_def",
+		"rut", "12.345.678-5",
+		"contact_phone", "+56 9 1234 5678",
+		"note", observability.Sensitive("free text"),
+		"owner", owner{ID: "o-1", Email: "ana@example.cl"},
+		slog.Group("req", "email", "ana@example.cl", "path", "/login"),
+	)
+
+	rec := records(t, buf)[0]
+	for _, key := range []string{"api_key", "Password", "password_hash", "refresh_token", "token_hash", "secret_hash", "ownerEmail", "x-api-key", "rut", "contact_phone", "note"} {
+		if rec[key] != observability.Redacted {
+			t.Errorf("%s = %v, want redacted", key, rec[key])
+		}
+	}
+	if rec["user_id"] != "u-1" || rec["route"] != "/login" {
+		t.Errorf("user_id = %v, route = %v", rec["user_id"], rec["route"])
+	}
+	o := rec["owner"].(map[string]any)
+	if o["id"] != "o-1" || o["email"] != observability.Redacted {
+		t.Errorf("owner = %v", o)
+	}
+	req := rec["req"].(map[string]any)
+	if req["path"] != "/login" || req["email"] != observability.Redacted {
+		t.Errorf("req = %v", req)
+	}
+}
+
+func TestTraceIDs(t *testing.T) {
+	logger, buf := newLogger(nil)
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+
+	logger.InfoContext(ctx, "traced")
+	logger.Info("untraced")
+
+	recs := records(t, buf)
+	if recs[0]["trace_id"] != span.SpanContext().TraceID().String() || recs[0]["span_id"] != span.SpanContext().SpanID().String() {
+		t.Errorf("traced record = %v", recs[0])
+	}
+	if _, ok := recs[1]["trace_id"]; ok {
+		t.Errorf("untraced record = %v", recs[1])
+	}
+}
+
+func TestContextIDsStayTopLevelInGroups(t *testing.T) {
+	logger, buf := newLogger(nil)
+	ctx := observability.WithRequestID(context.Background(), "req-1")
+
+	logger.With("service", "booking").WithGroup("payment").With("password", "x").WithGroup("card").
+		InfoContext(ctx, "charged", "last4", "4242")
+	logger.WithGroup("empty").InfoContext(ctx, "no attrs")
+
+	recs := records(t, buf)
+	rec := recs[0]
+	if rec["request_id"] != "req-1" || rec["service"] != "booking" {
+		t.Errorf("record = %v, want request_id and service at the top level", rec)
+	}
+	payment, _ := rec["payment"].(map[string]any)
+	card, _ := payment["card"].(map[string]any)
+	if payment["password"] != observability.Redacted || card["last4"] != "4242" || payment["request_id"] != nil {
+		t.Errorf("payment = %v", payment)
+	}
+	if _, ok := recs[1]["empty"]; ok || recs[1]["request_id"] != "req-1" {
+		t.Errorf("record with an empty group = %v", recs[1])
+	}
+}
+
+func TestDebugSampling(t *testing.T) {
+	logger, buf := newLogger(&observability.HandlerOptions{
+		DebugSampling: &observability.DebugSampling{Tick: time.Minute, First: 2, Thereafter: 3},
+	})
+	for range 10 {
+		logger.Debug("cache miss")
+		logger.Info("booking created")
+	}
+	logger.Debug("other message")
+
+	counts := map[string]int{}
+	for _, rec := range records(t, buf) {
+		counts[rec["msg"].(string)]++
+	}
+	// 2 first, then the 3rd and 6th of the remaining 8.
+	if counts["cache miss"] != 4 || counts["booking created"] != 10 || counts["other message"] != 1 {
+		t.Errorf("counts = %v", counts)
+	}
+}
+
+func TestLogLevelAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		claims := &auth.Claims{UserID: "u-1", Role: c.GetHeader("X-Test-Role")}
+		c.Request = c.Request.WithContext(auth.WithClaims(c.Request.Context(), claims))
+	})
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	loglevel.NewAdminHandler(level, clock).RegisterRoutes(r.Group(""))
+
+	put := func(role, body string) int {
+		req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Role", role)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := put("owner", `{"level":"debug"}`); code != http.StatusForbidden {
+		t.Errorf("non-admin: status = %d", code)
+	}
+	if code := put("admin", `{"level":"verbose"}`); code != http.StatusBadRequest {
+		t.Errorf("unknown level: status = %d", code)
+	}
+	if code := put("admin", `{"level":"debug","duration_seconds":60}`); code != http.StatusOK || level.Level() != slog.LevelDebug {
+		t.Fatalf("status = %d, level = %v", code, level.Level())
+	}
+	clock.Advance(59 * time.Second)
+	if level.Level() != slog.LevelDebug {
+		t.Errorf("level before the change ends = %v, want DEBUG", level.Level())
+	}
+	clock.Advance(time.Second)
+	if level.Level() != slog.LevelInfo {
+		t.Errorf("level after temporary change = %v, want INFO", level.Level())
+	}
+	if code := put("admin", `{"level":"warn"}`); code != http.StatusOK || level.Level() != slog.LevelWarn {
+		t.Errorf("status = %d, level = %v", code, level.Level())
+	}
+}
+
+// fakeClock runs AfterFunc callbacks when Advance reaches them.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Time
+	f       func()
+	stopped bool
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		active := !t.stopped
+		t.stopped = true
+		return active
+	}
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	var due []func()
+	for _, t := range c.timers {
+		if !t.stopped && !t.at.After(c.now) {
+			t.stopped = true
+			due = append(due, t.f)
+		}
+	}
+	c.mu.Unlock()
+	for _, f := range due {
+		f()
+	}
+}
@@ -0,0 +1,124 @@
+// internal/shared/observability/loglevel/http.go
+package loglevel
+
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"api/booking/internal/shared/middleware"
+	"api/booking/internal/shared/server"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler changes the log level of this instance at runtime. Mount it
+// on a group that already runs AuthRequired.
+type AdminHandler struct {
+	level *slog.LevelVar
+	clock Clock
+
+	mu        sync.Mutex
+	base      slog.Level  // Level to go back to when a temporary change ends
+	stop      func() bool // Cancels the pending revert
+	revertsAt *time.Time
+	changes   int // Lets a timer that fired during a newer change give up
+}
+
+// Clock schedules the end of temporary changes. SystemClock is the real
+// one; tests pass a fake they advance by hand.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
+
+func (SystemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+func NewAdminHandler(level *slog.LevelVar, clock Clock) *AdminHandler {
+	return &AdminHandler{level: level, clock: clock, base: level.Level()}
+}
+
+func (h *AdminHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	levels := rg.Group("/admin/log-level", middleware.RequireRole("admin"))
+	{
+		levels.GET("", h.Get)
+		levels.PUT("", h.Set)
+	}
+}
+
+type LevelResponse struct {
+	Level     string     `json:"level"`
+	RevertsAt *time.Time `json:"reverts_at"`
+}
+
+type SetLevelRequest struct {
+	Level string `json:"level" binding:"required"`
+	// Go back to the previous level after this long; 0 keeps the change
+	// until the next one or a restart. Up to 24h.
+	DurationSeconds int `json:"duration_seconds" binding:"omitempty,min=0,max=86400"`
+}
+
+func (h *AdminHandler) Get(c *gin.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	server.OK(c, http.StatusOK, h.response())
+}
+
+func (h *AdminHandler) Set(c *gin.Context) {
+	var req SetLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		server.FailWithDetails(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body", server.ValidationDetails(c, err))
+		return
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+		server.Fail(c, http.StatusBadRequest, "VALIDATION_ERROR", "level must be debug, info, warn or error")
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.changes++
+	if h.stop != nil {
+		h.stop()
+		h.stop, h.revertsAt = nil, nil
+	}
+	if req.DurationSeconds > 0 {
+		d := time.Duration(req.DurationSeconds) * time.Second
+		at := h.clock.Now().Add(d)
+		h.revertsAt = &at
+		change := h.changes
+		h.stop = h.clock.AfterFunc(d, func() { h.restore(change) })
+	} else {
+		h.base = level
+	}
+	h.level.Set(level)
+
+	slog.InfoContext(c.Request.Context(), "log level changed",
+		"level", level.String(),
+		"user_id", c.GetString("user_id"),
+		"duration_seconds", req.DurationSeconds,
+	)
+	server.OK(c, http.StatusOK, h.response())
+}
+
+func (h *AdminHandler) restore(change int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if change != h.changes {
+		return
+	}
+	h.level.Set(h.base)
+	h.stop, h.revertsAt = nil, nil
+	slog.Info("log level restored", "level", h.base.String())
+}
+
+func (h *AdminHandler) response() LevelResponse {
+	return LevelResponse{Level: h.level.Level().String(), RevertsAt: h.revertsAt}
+}
@@ -0,0 +1,43 @@
+// internal/shared/observability/logs.go
+package observability
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+)
+
+// NewLogHandler sends records to console and, through the otelslog bridge,
+// to the logger provider Setup installed. console decides the level for
+// both, so the runtime level applies to exported logs too. Wrap the result
+// in NewContextHandler so exported records are redacted as well.
+func NewLogHandler(console slog.Handler, serviceName string) slog.Handler {
+	return &teeHandler{console: console, otlp: otelslog.NewHandler(serviceName)}
+}
+
+type teeHandler struct {
+	console slog.Handler
+	otlp    slog.Handler
+}
+
+func (h *teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.console.Enabled(ctx, level)
+}
+
+func (h *teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	err := h.console.Handle(ctx, r.Clone())
+	if h.otlp.Enabled(ctx, r.Level) {
+		err = errors.Join(err, h.otlp.Handle(ctx, r))
+	}
+	return err
+}
+
+func (h *teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &teeHandler{console: h.console.WithAttrs(attrs), otlp: h.otlp.WithAttrs(attrs)}
+}
+
+func (h *teeHandler) WithGroup(name string) slog.Handler {
+	return &teeHandler{console: h.console.WithGroup(name), otlp: h.otlp.WithGroup(name)}
+}
@@ -0,0 +1,48 @@
+// internal/shared/observability/prometheus.go
+package observability
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+// DurationBuckets are the histogram bounds, in seconds, for HTTP and gRPC
+// latency. The SDK default is meant for milliseconds.
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// scrapeHandler is set by Setup; each call gets a fresh registry so
+// collectors are never registered twice.
+var scrapeHandler atomic.Pointer[http.Handler]
+
+func newPrometheusReader() (metric.Reader, error) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	reader, err := otelprom.New(otelprom.WithRegisterer(reg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+	}
+	h := promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+	scrapeHandler.Store(&h)
+	return reader, nil
+}
+
+// MetricsHandler serves the Prometheus scrape endpoint; it's 404 unless
+// Setup ran with Prometheus enabled. It has no auth of its own: serve it on
+// the admin listener (ADMIN_PORT), never on the public API.
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := scrapeHandler.Load()
+		if h == nil {
+			http.NotFound(w, r)
+			return
+		}
+		(*h).ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,117 @@
+// internal/shared/observability/prometheus_test.go
+package observability_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"api/booking/internal/shared/config"
+	"api/booking/internal/shared/middleware"
+	"api/booking/internal/shared/observability"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	observability.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/metrics status = %d", rec.Code)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+// hasSample reports whether a sample of metric carries all the labels.
+func hasSample(body, metric string, labels ...string) bool {
+	for _, line := range strings.Split(body, "\n") {
+		if !strings.HasPrefix(line, metric+"{") {
+			continue
+		}
+		matched := true
+		for _, l := range labels {
+			matched = matched && strings.Contains(line, l)
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPrometheusRED(t *testing.T) {
+	shutdown, err := observability.Setup(context.Background(), config.ObservabilityConfig{ServiceName: "booking", Prometheus: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.RecoveryWithWriter(io.Discard), middleware.Metrics())
+	r.GET("/bookings/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/panics", func(*gin.Context) { panic("boom") })
+	for _, path := range []string{"/bookings/b-1", "/bookings/b-2", "/no/such/route", "/panics"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t)
+	if !hasSample(body, "http_server_requests_total", `http_route="/bookings/:id"`, `http_response_status_code="200"`, "} 2") {
+		t.Errorf("no request count for the route template:\n%s", body)
+	}
+	if !hasSample(body, "http_server_requests_total", `http_route="unmatched"`, `http_response_status_code="404"`) {
+		t.Error("unmatched path not folded into one label")
+	}
+	if strings.Contains(body, "b-1") {
+		t.Error("raw path leaked into labels")
+	}
+	if !hasSample(body, "http_server_request_duration_seconds_bucket", `http_route="/bookings/:id"`, `le="0.005"`) {
+		t.Error("duration histogram missing or not in seconds")
+	}
+	if !hasSample(body, "http_server_active_requests", `http_route="/bookings/:id"`, "} 0") {
+		t.Error("in-flight gauge missing or not back to 0")
+	}
+	if !hasSample(body, "http_server_requests_total", `http_route="/panics"`, `http_response_status_code="500"`) ||
+		!hasSample(body, "http_server_active_requests", `http_route="/panics"`, "} 0") {
+		t.Error("panicking request not counted as a 500 or still in flight")
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(observability.UnaryServerMetrics()))
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(observability.UnaryClientMetrics()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	client := healthpb.NewHealthClient(conn)
+	_, _ = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	_, _ = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"}) // NotFound
+
+	body = scrape(t)
+	for _, side := range []string{"server", "client"} {
+		metric := "rpc_" + side + "_calls_total"
+		if !hasSample(body, metric, `rpc_service="grpc.health.v1.Health"`, `rpc_method="Check"`, `rpc_grpc_status_code="0"`) {
+			t.Errorf("%s: no OK call recorded", metric)
+		}
+		if !hasSample(body, metric, `rpc_method="Check"`, `rpc_grpc_status_code="5"`) {
+			t.Errorf("%s: NotFound call not recorded", metric)
+		}
+	}
+}
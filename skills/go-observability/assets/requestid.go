@@ -0,0 +1,51 @@
+// internal/shared/observability/requestid.go
+package observability
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID on HTTP requests and responses and
+// on NATS messages; RequestIDMetadataKey is its gRPC metadata equivalent.
+const (
+	RequestIDHeader      = "X-Request-ID"
+	RequestIDMetadataKey = "x-request-id"
+)
+
+type requestIDKey struct{}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestIDOrNew returns incoming if it's a usable ID, or a new UUID. IDs
+// come from clients and other services, so anything long or with
+// characters that could break log lines is replaced.
+func RequestIDOrNew(incoming string) string {
+	if validRequestID(incoming) {
+		return incoming
+	}
+	return uuid.NewString()
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
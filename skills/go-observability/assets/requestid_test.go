@@ -0,0 +1,153 @@
+// internal/shared/observability/requestid_test.go
+package observability_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"api/booking/internal/shared/middleware"
+	"api/booking/internal/shared/observability"
+	"api/booking/internal/shared/server"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestRequestIDOrNew(t *testing.T) {
+	if got := observability.RequestIDOrNew("req-123.abc"); got != "req-123.abc" {
+		t.Errorf("valid ID replaced with %q", got)
+	}
+	for _, bad := range []string{"", "has space", "line\nbreak", strings.Repeat("a", 129)} {
+		if got := observability.RequestIDOrNew(bad); got == bad || len(got) != 36 {
+			t.Errorf("RequestIDOrNew(%q) = %q, want a new UUID", bad, got)
+		}
+	}
+}
+
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(observability.NewContextHandler(slog.NewJSONHandler(&buf, nil), nil)).With("service", "booking")
+
+	ctx := observability.WithRequestID(context.Background(), "req-1")
+	logger.InfoContext(ctx, "with id")
+	logger.Info("without id")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var first, second map[string]any
+	_ = json.Unmarshal([]byte(lines[0]), &first)
+	_ = json.Unmarshal([]byte(lines[1]), &second)
+	if first["request_id"] != "req-1" || first["service"] != "booking" {
+		t.Errorf("record = %v", first)
+	}
+	if _, ok := second["request_id"]; ok {
+		t.Errorf("record without context has request_id: %v", second)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.RequestID())
+	r.GET("/fail", func(c *gin.Context) {
+		server.Fail(c, http.StatusNotFound, "NOT_FOUND", "Booking not found")
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		accept string
+	}{
+		{"kept", "req-from-client", server.MediaTypeJSON},
+		{"generated", "", server.MediaTypeJSON},
+		{"problem", "req-from-client", server.MediaTypeProblem},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+			req.Header.Set("Accept", tt.accept)
+			if tt.header != "" {
+				req.Header.Set(observability.RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(observability.RequestIDHeader)
+			if id == "" || (tt.header != "" && id != tt.header) {
+				t.Fatalf("response header = %q", id)
+			}
+			var body struct {
+				RequestID string `json:"request_id"`
+				Error     struct {
+					RequestID string `json:"request_id"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if got := body.RequestID + body.Error.RequestID; got != id {
+				t.Errorf("request_id in body = %q, want %q", got, id)
+			}
+		})
+	}
+}
+
+// recordingHealth captures the request ID the server handler sees.
+type recordingHealth struct {
+	*health.Server
+	seen string
+}
+
+func (h *recordingHealth) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	h.seen = observability.RequestIDFromContext(ctx)
+	return h.Server.Check(ctx, req)
+}
+
+func TestGRPCRequestID(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	hs := &recordingHealth{Server: health.NewServer()}
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(observability.UnaryServerRequestID()))
+	healthpb.RegisterHealthServer(s, hs)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(observability.UnaryClientRequestID()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	client := healthpb.NewHealthClient(conn)
+
+	var header metadata.MD
+	ctx := observability.WithRequestID(context.Background(), "req-42")
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header)); err != nil {
+		t.Fatal(err)
+	}
+	if hs.seen != "req-42" {
+		t.Errorf("server saw %q, want req-42", hs.seen)
+	}
+	if got := header.Get(observability.RequestIDMetadataKey); len(got) != 1 || got[0] != "req-42" {
+		t.Errorf("response header = %v", got)
+	}
+
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if hs.seen == "" {
+		t.Error("server didn't generate an ID for a call without one")
+	}
+}
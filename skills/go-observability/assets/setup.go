@@ -0,0 +1,156 @@
+// internal/shared/observability/setup.go
+package observability
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"api/booking/internal/shared/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Setup installs the global tracer, meter and logger providers, all
+// exporting over OTLP gRPC with the same resource, transport and headers,
+// plus a Prometheus reader for MetricsHandler when cfg.Prometheus is set.
+// The returned shutdown flushes and stops them; call it once on exit. Logs
+// reach the logger provider through NewLogHandler.
+func Setup(ctx context.Context, cfg config.ObservabilityConfig) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if cfg.Endpoint == "" && !cfg.Prometheus {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithAttributes(
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+			semconv.DeploymentEnvironment(cfg.Environment),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	var shutdowns []func(context.Context) error
+	shutdownAll := func(ctx context.Context) error {
+		var errs []error
+		for i := len(shutdowns) - 1; i >= 0; i-- {
+			errs = append(errs, shutdowns[i](ctx))
+		}
+		return errors.Join(errs...)
+	}
+	defer func() {
+		if err != nil {
+			_ = shutdownAll(ctx)
+		}
+	}()
+
+	var readers []metric.Reader
+	if cfg.Prometheus {
+		reader, err := newPrometheusReader()
+		if err != nil {
+			return nil, err
+		}
+		readers = append(readers, reader)
+	}
+
+	if cfg.Endpoint != "" {
+		creds, err := collectorCredentials(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		traceExporter, err := otlptracegrpc.New(ctx,
+			otlptracegrpc.WithEndpoint(cfg.Endpoint),
+			otlptracegrpc.WithTLSCredentials(creds),
+			otlptracegrpc.WithHeaders(cfg.Headers),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+		}
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(traceExporter),
+			sdktrace.WithResource(res),
+			// Follow the caller's decision so a trace is never half-sampled
+			// across services; the ratio only applies where a trace starts.
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		)
+		shutdowns = append(shutdowns, tp.Shutdown)
+		otel.SetTracerProvider(tp)
+
+		logExporter, err := otlploggrpc.New(ctx,
+			otlploggrpc.WithEndpoint(cfg.Endpoint),
+			otlploggrpc.WithTLSCredentials(creds),
+			otlploggrpc.WithHeaders(cfg.Headers),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create log exporter: %w", err)
+		}
+		lp := sdklog.NewLoggerProvider(
+			sdklog.WithProcessor(sdklog.NewBatchProcessor(logExporter)),
+			sdklog.WithResource(res),
+		)
+		shutdowns = append(shutdowns, lp.Shutdown)
+		global.SetLoggerProvider(lp)
+
+		metricExporter, err := otlpmetricgrpc.New(ctx,
+			otlpmetricgrpc.WithEndpoint(cfg.Endpoint),
+			otlpmetricgrpc.WithTLSCredentials(creds),
+			otlpmetricgrpc.WithHeaders(cfg.Headers),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metric exporter: %w", err)
+		}
+		readers = append(readers, metric.NewPeriodicReader(metricExporter, metric.WithInterval(cfg.MetricInterval)))
+	}
+
+	opts := []metric.Option{metric.WithResource(res)}
+	for _, r := range readers {
+		opts = append(opts, metric.WithReader(r))
+	}
+	mp := metric.NewMeterProvider(opts...)
+	shutdowns = append(shutdowns, mp.Shutdown)
+	otel.SetMeterProvider(mp)
+
+	return shutdownAll, nil
+}
+
+func collectorCredentials(cfg config.ObservabilityConfig) (credentials.TransportCredentials, error) {
+	if cfg.Insecure {
+		return insecure.NewCredentials(), nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read collector CA: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in collector CA file %s", cfg.CAFile)
+		}
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
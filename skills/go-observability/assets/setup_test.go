@@ -0,0 +1,60 @@
+// internal/shared/observability/setup_test.go
+package observability_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"api/booking/internal/shared/config"
+	"api/booking/internal/shared/observability"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := observability.Setup(context.Background(), config.ObservabilityConfig{ServiceName: "booking"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+	if fields := otel.GetTextMapPropagator().Fields(); len(fields) == 0 {
+		t.Error("propagator not installed with export disabled")
+	}
+}
+
+func TestSetupExportsAndShutsDown(t *testing.T) {
+	cfg := config.ObservabilityConfig{
+		ServiceName:    "booking",
+		ServiceVersion: "test",
+		Environment:    "test",
+		Endpoint:       "127.0.0.1:1", // Nothing listens; exporters connect lazily
+		Insecure:       true,
+		Headers:        map[string]string{"x-api-key": "k"},
+		SampleRatio:    0.5,
+		MetricInterval: time.Minute,
+	}
+	shutdown, err := observability.Setup(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_ = shutdown(ctx) // Flushing to a missing collector fails; it must not hang
+}
+
+func TestSetupCollectorCA(t *testing.T) {
+	bad := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bad, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, file := range map[string]string{"missing": filepath.Join(t.TempDir(), "none.pem"), "invalid": bad} {
+		_, err := observability.Setup(context.Background(), config.ObservabilityConfig{Endpoint: "collector:4317", CAFile: file})
+		if err == nil {
+			t.Errorf("%s CA file: expected an error", name)
+		}
+	}
+}
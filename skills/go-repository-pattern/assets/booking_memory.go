@@ -0,0 +1,114 @@
+// internal/booking/infrastructure/repository/memory.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"api/booking/internal/booking/domain"
+	"api/booking/internal/shared/pagination"
+	"api/booking/internal/shared/query"
+)
+
+type InMemoryBookingRepository struct {
+	mu       sync.RWMutex
+	bookings map[string]*domain.Booking
+}
+
+func NewInMemoryBookingRepository() *InMemoryBookingRepository {
+	return &InMemoryBookingRepository{
+		bookings: make(map[string]*domain.Booking),
+	}
+}
+
+func (r *InMemoryBookingRepository) Save(ctx context.Context, booking *domain.Booking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bookings[booking.ID.String()] = booking
+	return nil
+}
+
+func (r *InMemoryBookingRepository) FindByID(ctx context.Context, id domain.BookingID) (*domain.Booking, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	b, ok := r.bookings[id.String()]
+	if !ok {
+		return nil, domain.ErrBookingNotFound
+	}
+	return b, nil
+}
+
+// bookingFields reads the BookingQuery fields for query.Match.
+var bookingFields = query.Accessors[*domain.Booking]{
+	"status":       func(b *domain.Booking) any { return string(b.Status) },
+	"service_type": func(b *domain.Booking) any { return string(b.ServiceType) },
+	"start_at":     func(b *domain.Booking) any { return b.StartAt },
+}
+
+// ListByOwner mirrors the Postgres filters and keyset ordering on
+// (start_at, id), and rejects the same cursors.
+func (r *InMemoryBookingRepository) ListByOwner(ctx context.Context, ownerID string, q query.Query, page pagination.Keyset) (pagination.KeysetPage[*domain.Booking], error) {
+	var position func(*domain.Booking) int
+	if page.Cursor != nil {
+		at, id, err := cursorPosition(page.Cursor)
+		if err != nil {
+			return pagination.KeysetPage[*domain.Booking]{}, err
+		}
+		position = func(b *domain.Booking) int {
+			if c := b.StartAt.Compare(at); c != 0 {
+				return c
+			}
+			return strings.Compare(b.ID.String(), id)
+		}
+	}
+
+	match := query.Match(q, bookingFields)
+	r.mu.RLock()
+	var owned []*domain.Booking
+	for _, b := range r.bookings {
+		if b.OwnerID != ownerID {
+			continue
+		}
+		ok, err := match(b)
+		if err != nil {
+			r.mu.RUnlock()
+			return pagination.KeysetPage[*domain.Booking]{}, fmt.Errorf("failed to filter bookings: %w", err)
+		}
+		if ok {
+			owned = append(owned, b)
+		}
+	}
+	r.mu.RUnlock()
+
+	// Walk in query order: descending rows come after the cursor when they
+	// compare below it.
+	desc := newestFirstQuery(q) != page.Backward()
+	slices.SortFunc(owned, oldestFirst)
+	if desc {
+		slices.Reverse(owned)
+	}
+
+	var rows []*domain.Booking
+	for _, b := range owned {
+		if position != nil {
+			if p := position(b); p == 0 || (p < 0) != desc {
+				continue
+			}
+		}
+		rows = append(rows, b)
+		if len(rows) == page.FetchLimit() {
+			break
+		}
+	}
+	return pagination.NewKeysetPage(rows, page, bookingCursor), nil
+}
+
+func oldestFirst(a, b *domain.Booking) int {
+	if c := a.StartAt.Compare(b.StartAt); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID.String(), b.ID.String())
+}
@@ -0,0 +1,134 @@
+// internal/booking/infrastructure/repository/memory_test.go
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"api/booking/internal/booking/domain"
+	"api/booking/internal/booking/infrastructure/repository"
+	"api/booking/internal/shared/pagination"
+	"api/booking/internal/shared/query"
+)
+
+var base = time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// seed saves seven bookings for "ana" with start times that repeat, so the
+// ID tie-breaker matters, plus one for another owner.
+func seed(t *testing.T) (*repository.InMemoryBookingRepository, []domain.BookingID) {
+	t.Helper()
+	r := repository.NewInMemoryBookingRepository()
+	bookingIDs := []domain.BookingID{
+		"0b6c1a6e-0000-4000-8000-000000000001",
+		"0b6c1a6e-0000-4000-8000-000000000002",
+		"0b6c1a6e-0000-4000-8000-000000000003",
+		"0b6c1a6e-0000-4000-8000-000000000004",
+		"0b6c1a6e-0000-4000-8000-000000000005",
+		"0b6c1a6e-0000-4000-8000-000000000006",
+		"0b6c1a6e-0000-4000-8000-000000000007",
+	}
+	for i, id := range bookingIDs {
+		b := &domain.Booking{ID: id, OwnerID: "ana", Status: "pending", StartAt: base.Add(time.Duration(i/2) * time.Hour)}
+		if err := r.Save(context.Background(), b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := &domain.Booking{ID: "0b6c1a6e-0000-4000-8000-000000000099", OwnerID: "bob", StartAt: base}
+	if err := r.Save(context.Background(), other); err != nil {
+		t.Fatal(err)
+	}
+	return r, bookingIDs
+}
+
+func TestListByOwner_KeysetPaging(t *testing.T) {
+	tests := []struct {
+		name        string
+		q           query.Query
+		newestFirst bool
+	}{
+		{"default", query.Query{}, true},
+		{"oldest first", query.Query{Sorts: []query.Sort{{Field: "start_at"}}}, false},
+		{"newest first", query.Query{Sorts: []query.Sort{{Field: "start_at", Desc: true}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r, want := seed(t)
+			if tt.newestFirst {
+				slices.Reverse(want)
+			}
+
+			// Forward through every page.
+			var (
+				pages []pagination.KeysetPage[*domain.Booking]
+				got   []domain.BookingID
+				req   = pagination.Keyset{Limit: 3}
+			)
+			for {
+				page, err := r.ListByOwner(ctx, "ana", tt.q, req)
+				if err != nil {
+					t.Fatalf("ListByOwner() error = %v", err)
+				}
+				pages = append(pages, page)
+				for _, b := range page.Items {
+					got = append(got, b.ID)
+				}
+				if page.Next == nil {
+					break
+				}
+				req.Cursor = page.Next
+			}
+			if !slices.Equal(got, want) {
+				t.Fatalf("forward pages = %v, want %v", got, want)
+			}
+			if len(pages) != 3 || len(pages[2].Items) != 1 {
+				t.Fatalf("got %d pages, want 3 with one item on the last", len(pages))
+			}
+			if pages[0].Prev != nil || pages[1].Prev == nil || pages[2].Prev == nil {
+				t.Error("Prev must be set on every page but the first")
+			}
+
+			// And back from the last page to the first.
+			for i := len(pages) - 1; i > 0; i-- {
+				back, err := r.ListByOwner(ctx, "ana", tt.q, pagination.Keyset{Limit: 3, Cursor: pages[i].Prev})
+				if err != nil {
+					t.Fatalf("ListByOwner(backward) error = %v", err)
+				}
+				if !slices.Equal(ids(back.Items), ids(pages[i-1].Items)) {
+					t.Errorf("backward from page %d = %v, want %v", i, ids(back.Items), ids(pages[i-1].Items))
+				}
+				if back.Next == nil {
+					t.Errorf("backward page %d has no Next", i-1)
+				}
+				if (back.Prev == nil) != (i-1 == 0) {
+					t.Errorf("backward page %d: Prev = %+v", i-1, back.Prev)
+				}
+			}
+		})
+	}
+}
+
+func TestListByOwner_RejectsMalformedCursors(t *testing.T) {
+	r, _ := seed(t)
+	cursors := []pagination.Cursor{
+		{Key: "yesterday", ID: "0b6c1a6e-0000-4000-8000-000000000001"},
+		{Key: base.Format(time.RFC3339Nano), ID: "1 OR 1=1"},
+	}
+	for _, c := range cursors {
+		_, err := r.ListByOwner(context.Background(), "ana", query.Query{}, pagination.Keyset{Limit: 3, Cursor: &c})
+		if !errors.Is(err, pagination.ErrInvalidCursor) {
+			t.Errorf("ListByOwner(%+v) error = %v, want ErrInvalidCursor", c, err)
+		}
+	}
+}
+
+func ids(bookings []*domain.Booking) []domain.BookingID {
+	out := make([]domain.BookingID, len(bookings))
+	for i, b := range bookings {
+		out[i] = b.ID
+	}
+	return out
+}
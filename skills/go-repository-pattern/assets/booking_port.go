@@ -0,0 +1,32 @@
+// internal/booking/domain/port.go
+package domain
+
+import (
+	"context"
+
+	"api/booking/internal/shared/pagination"
+	"api/booking/internal/shared/query"
+)
+
+type Repository interface {
+	Save(ctx context.Context, booking *Booking) error
+	FindByID(ctx context.Context, id BookingID) (*Booking, error)
+	// ListByOwner returns the owner's bookings matching q, ordered by
+	// (start_at, id) — descending unless q sorts by start_at ascending — one
+	// keyset page at a time. A cursor that doesn't point at a valid position
+	// is pagination.ErrInvalidCursor.
+	ListByOwner(ctx context.Context, ownerID string, q query.Query, page pagination.Keyset) (pagination.KeysetPage[*Booking], error)
+}
+
+// BookingQuery whitelists the filters and sorts of booking lists. Keyset
+// pages can only follow their cursor key, so start_at is the one sortable
+// field.
+var BookingQuery = query.Schema{
+	Fields: map[string]query.Field{
+		"status":       {Column: "status", Enum: []string{"pending", "confirmed", "cancelled"}},
+		"service_type": {Column: "service_type", Enum: []string{"walk", "hosting", "visit", "specialized"}},
+		"start_at":     {Column: "start_at", Type: query.Time, Ops: []query.Op{query.OpGte, query.OpGt, query.OpLt, query.OpLte}, Sortable: true},
+	},
+	TieBreaker:  "id",
+	DefaultSort: []query.Sort{{Field: "start_at", Desc: true}},
+}
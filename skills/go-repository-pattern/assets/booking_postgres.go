@@ -0,0 +1,154 @@
+// internal/booking/infrastructure/repository/postgres.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"api/booking/internal/booking/domain"
+	"api/booking/internal/shared/pagination"
+	"api/booking/internal/shared/query"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// List queries are built with pgx rather than sqlc: the keyset condition
+// and its direction depend on the request.
+type PostgresBookingRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresBookingRepository(pool *pgxpool.Pool) *PostgresBookingRepository {
+	return &PostgresBookingRepository{pool: pool}
+}
+
+const bookingColumns = "id, owner_id, caregiver_id, service_type, status, start_at, end_at, created_at, updated_at"
+
+type bookingRow struct {
+	ID          string    `db:"id"`
+	OwnerID     string    `db:"owner_id"`
+	CaregiverID string    `db:"caregiver_id"`
+	ServiceType string    `db:"service_type"`
+	Status      string    `db:"status"`
+	StartAt     time.Time `db:"start_at"`
+	EndAt       time.Time `db:"end_at"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (r *PostgresBookingRepository) Save(ctx context.Context, b *domain.Booking) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO bookings (`+bookingColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE SET status = EXCLUDED.status, updated_at = EXCLUDED.updated_at`,
+		b.ID.String(), b.OwnerID, b.CaregiverID, string(b.ServiceType), string(b.Status),
+		b.StartAt, b.EndAt, b.CreatedAt, b.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save booking: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresBookingRepository) FindByID(ctx context.Context, id domain.BookingID) (*domain.Booking, error) {
+	rows, _ := r.pool.Query(ctx, "SELECT "+bookingColumns+" FROM bookings WHERE id = $1", id.String())
+	row, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[bookingRow])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrBookingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find booking: %w", err)
+	}
+	return bookingToDomain(row), nil
+}
+
+// ListByOwner walks (start_at, id) in the sort's direction, after the
+// filters. With an index on (owner_id, start_at, id) every page is a range
+// scan, however deep.
+func (r *PostgresBookingRepository) ListByOwner(ctx context.Context, ownerID string, q query.Query, page pagination.Keyset) (pagination.KeysetPage[*domain.Booking], error) {
+	sql := "SELECT " + bookingColumns + " FROM bookings WHERE owner_id = $1"
+	args := []any{ownerID}
+	if where, whereArgs := domain.BookingQuery.Where(q, 2); where != "" {
+		sql += " AND " + where
+		args = append(args, whereArgs...)
+	}
+
+	// Walking backward flips both the comparison and the order.
+	desc := newestFirstQuery(q) != page.Backward()
+	if page.Cursor != nil {
+		at, id, err := cursorPosition(page.Cursor)
+		if err != nil {
+			return pagination.KeysetPage[*domain.Booking]{}, err
+		}
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		n := len(args)
+		sql += fmt.Sprintf(" AND (start_at, id) %s ($%d, $%d::uuid)", op, n+1, n+2)
+		args = append(args, at, id)
+	}
+	order := "start_at, id"
+	if desc {
+		order = "start_at DESC, id DESC"
+	}
+	sql += " ORDER BY " + order + " LIMIT " + strconv.Itoa(page.FetchLimit())
+
+	rows, _ := r.pool.Query(ctx, sql, args...)
+	found, err := pgx.CollectRows(rows, pgx.RowToStructByName[bookingRow])
+	if err != nil {
+		return pagination.KeysetPage[*domain.Booking]{}, fmt.Errorf("failed to list bookings: %w", err)
+	}
+
+	bookings := make([]*domain.Booking, 0, len(found))
+	for _, row := range found {
+		bookings = append(bookings, bookingToDomain(row))
+	}
+	return pagination.NewKeysetPage(bookings, page, bookingCursor), nil
+}
+
+// newestFirstQuery reports the list direction; BookingQuery only sorts by
+// start_at, so the first sort decides.
+func newestFirstQuery(q query.Query) bool {
+	return len(q.Sorts) == 0 || q.Sorts[0].Desc
+}
+
+func bookingCursor(b *domain.Booking) pagination.Cursor {
+	return pagination.Cursor{Key: b.StartAt.UTC().Format(time.RFC3339Nano), ID: b.ID.String()}
+}
+
+// cursorPosition checks a client-supplied cursor before it reaches SQL, so
+// a tampered one is a 400 rather than a uuid cast error. The ID comes back
+// in canonical form, which orders like the uuid column.
+func cursorPosition(c *pagination.Cursor) (time.Time, string, error) {
+	at, err := time.Parse(time.RFC3339Nano, c.Key)
+	if err != nil {
+		return time.Time{}, "", pagination.ErrInvalidCursor
+	}
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return time.Time{}, "", pagination.ErrInvalidCursor
+	}
+	return at, id.String(), nil
+}
+
+// bookingToDomain maps a stored row straight onto the entity. NewBooking is
+// for new bookings: it would assign a fresh ID and re-run the creation rules,
+// which rows that were valid when saved must not fail later.
+func bookingToDomain(row bookingRow) *domain.Booking {
+	return &domain.Booking{
+		ID:          domain.BookingID(row.ID),
+		OwnerID:     row.OwnerID,
+		CaregiverID: row.CaregiverID,
+		ServiceType: domain.ServiceType(row.ServiceType),
+		Status:      domain.Status(row.Status),
+		StartAt:     row.StartAt,
+		EndAt:       row.EndAt,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
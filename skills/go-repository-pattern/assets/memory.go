@@ -22,6 +22,11 @@ func NewInMemoryUserRepository() *InMemoryUserRepository {
 func (r *InMemoryUserRepository) Save(ctx context.Context, user *domain.User) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	for id, u := range r.users {
+		if u.Email == user.Email && id != user.ID.String() {
+			return domain.ErrEmailTaken
+		}
+	}
 	r.users[user.ID.String()] = user
 	return nil
 }
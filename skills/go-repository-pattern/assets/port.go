@@ -4,6 +4,7 @@ package domain
 import "context"
 
 type UserRepository interface {
+	// Save returns ErrEmailTaken when another user has the email.
 	Save(ctx context.Context, user *User) error
 	FindByID(ctx context.Context, id UserID) (*User, error)
 	FindByEmail(ctx context.Context, email Email) (*User, error)
@@ -9,6 +9,7 @@ import (
 	"api/auth/internal/auth/domain"
 	"api/auth/internal/auth/infrastructure/repository/db"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -30,6 +31,9 @@ func (r *PostgresUserRepository) Save(ctx context.Context, user *domain.User) er
 		CreatedAt:    user.CreatedAt,
 		UpdatedAt:    user.UpdatedAt,
 	})
+	if isEmailTaken(err) {
+		return domain.ErrEmailTaken
+	}
 	if err != nil {
 		return fmt.Errorf("failed to save user: %w", err)
 	}
@@ -55,6 +59,17 @@ func (r *PostgresUserRepository) ExistsByEmail(ctx context.Context, email domain
 	return exists, nil
 }
 
+// isEmailTaken reports a unique violation (SQLSTATE 23505) on users.email.
+// The insert is the check: a separate lookup first would race with a
+// concurrent registration.
+func isEmailTaken(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return false
+	}
+	return pgErr.ConstraintName == "users_email_key" || pgErr.ConstraintName == "idx_users_email"
+}
+
 // toDomain maps sqlc-generated struct to domain entity
 func toDomain(row db.User) *domain.User {
 	return domain.ReconstructUser(
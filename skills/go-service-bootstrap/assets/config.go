@@ -2,19 +2,25 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Env       string
-	HTTP      HTTPConfig
-	GRPC      GRPCConfig
-	Database  DatabaseConfig
-	Messaging MessagingConfig
-	Storage   StorageConfig
+	Env           string
+	HTTP          HTTPConfig
+	GRPC          GRPCConfig
+	Admin         AdminConfig
+	Database      DatabaseConfig
+	Messaging     MessagingConfig
+	Storage       StorageConfig
+	Auth          AuthConfig
+	TLS           TLSConfig
+	Observability ObservabilityConfig
 }
 
 type HTTPConfig struct {
@@ -25,6 +31,12 @@ type GRPCConfig struct {
 	Port int
 }
 
+// AdminConfig is the internal listener for operational endpoints such as
+// /metrics. It must not be routed from the public ingress.
+type AdminConfig struct {
+	Port int
+}
+
 type DatabaseConfig struct {
 	Host     string
 	Port     int
@@ -47,17 +59,125 @@ type StorageConfig struct {
 	Endpoint       string
 	Region         string
 	ForcePathStyle bool
+	KeyFile        string // KEK file for EncryptedStore; empty disables client-side encryption
+	WebhookToken   string // Shared secret for bucket notifications; empty refuses them all
+	Buckets        BucketConfig
+	Lifecycle      LifecycleConfig
+}
+
+type BucketConfig struct {
+	Uploads string
+	Exports string
+	Backups string
+}
+
+type LifecycleConfig struct {
+	Enabled  bool
+	DryRun   bool // Report only
+	Interval time.Duration
+	Rules    []LifecycleRule
+}
+
+// LifecycleRule applies to objects in Bucket under Prefix. Zero values disable
+// each policy. Rules run in order; an object acted on by one rule is skipped by
+// later ones. storage.LifecycleRunner validates and applies them.
+type LifecycleRule struct {
+	ID              string `json:"id"`
+	Bucket          string `json:"bucket"`
+	Prefix          string `json:"prefix"`
+	ExpireAfterDays int    `json:"expire_after_days,omitempty"`
+	// KeepLast keeps the N newest versions of each object. A "version" is an
+	// object with the same key once the date segment (and a run segment under
+	// it, see storage.RunKey) is removed, e.g. every
+	// backups/booking/{date}/booking-db-full.sql.gz.
+	KeepLast      int    `json:"keep_last,omitempty"`
+	ColdAfterDays int    `json:"cold_after_days,omitempty"`
+	ColdPrefix    string `json:"cold_prefix,omitempty"` // e.g. "cold/"
+}
+
+// DefaultLifecycleRules covers the three concern buckets. Override per
+// environment with STORAGE_LIFECYCLE_RULES (JSON array of LifecycleRule).
+func DefaultLifecycleRules(b BucketConfig) []LifecycleRule {
+	return []LifecycleRule{
+		{ID: "backups-keep-14", Bucket: b.Backups, Prefix: "backups/", KeepLast: 14},
+		{ID: "exports-cold-30", Bucket: b.Exports, Prefix: "exports/", ColdAfterDays: 30, ColdPrefix: "cold/"},
+		{ID: "exports-expire-365", Bucket: b.Exports, Prefix: "cold/exports/", ExpireAfterDays: 365},
+	}
+}
+
+// AuthConfig configures access-token validation. Set JWKSURL in deployed
+// environments; PublicKeyFile (PEM, or a .json JWKS) is for local runs.
+// The Signing* and *TokenTTL fields are only read by the auth service.
+type AuthConfig struct {
+	Issuer        string
+	Audience      string
+	JWKSURL       string
+	JWKSCacheTTL  time.Duration
+	PublicKeyFile string
+	ClockSkew     time.Duration
+
+	SigningKeyFile  string // PKCS#8 PEM private key
+	SigningKeyID    string // "kid"; change it whenever the key changes
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// TLSConfig secures the HTTP and gRPC servers and outgoing gRPC clients.
+// With CAFile set, internal peers must present a certificate signed by that
+// CA (mTLS); AllowedClientSANs limits the callers internal servers accept
+// and AllowedServerSANs the servers our clients dial. Files are re-read
+// when they change, so rotated certificates apply without restart.
+type TLSConfig struct {
+	Enabled  bool
+	CertFile string
+	KeyFile  string
+	CAFile   string
+	// DNS names or URIs (spiffe://...). Empty inbound accepts any client the
+	// CA signed; empty outbound checks the dialed host name instead.
+	AllowedClientSANs []string
+	AllowedServerSANs []string
+	ReloadInterval    time.Duration
+}
+
+// ObservabilityConfig configures OTLP export of traces, metrics and logs
+// to the collector. An empty Endpoint turns export off; logs still go to
+// stdout and trace context is still propagated. Prometheus serves metrics
+// on the admin listener's /metrics as well, with or without an Endpoint.
+type ObservabilityConfig struct {
+	ServiceName    string
+	ServiceVersion string
+	Environment    string
+	Endpoint       string            // Collector OTLP gRPC host:port
+	Insecure       bool              // Plaintext, e.g. to a local or sidecar collector
+	CAFile         string            // Verifies the collector; system roots when empty
+	Headers        map[string]string // Sent on every export, e.g. a hosted backend's API key
+	SampleRatio    float64           // Share of new traces kept; callers' decisions are followed
+	MetricInterval time.Duration
+	Prometheus     bool
 }
 
 func Load() (*Config, error) {
+	env := getEnv("APP_ENV", "development")
+	buckets := BucketConfig{
+		Uploads: getEnv("STORAGE_BUCKET_UPLOADS", "bastet-uploads"),
+		Exports: getEnv("STORAGE_BUCKET_EXPORTS", "bastet-exports"),
+		Backups: getEnv("STORAGE_BUCKET_BACKUPS", "bastet-backups"),
+	}
+	rules, err := getEnvLifecycleRules("STORAGE_LIFECYCLE_RULES", DefaultLifecycleRules(buckets))
+	if err != nil {
+		return nil, err
+	}
 	return &Config{
-		Env: getEnv("APP_ENV", "development"),
+		Env: env,
 		HTTP: HTTPConfig{
 			Port: getEnvInt("HTTP_PORT", 8080),
 		},
 		GRPC: GRPCConfig{
 			Port: getEnvInt("GRPC_PORT", 9090),
 		},
+		Admin: AdminConfig{
+			Port: getEnvInt("ADMIN_PORT", 8081),
+		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnvInt("DB_PORT", 5432),
@@ -78,6 +198,49 @@ func Load() (*Config, error) {
 			Endpoint:       getEnv("STORAGE_ENDPOINT", "http://localhost:9000"),
 			Region:         getEnv("STORAGE_REGION", "us-east-1"),
 			ForcePathStyle: getEnvBool("STORAGE_FORCE_PATH_STYLE", true),
+			KeyFile:        getEnv("STORAGE_KEY_FILE", ""),
+			WebhookToken:   getEnv("STORAGE_WEBHOOK_TOKEN", ""),
+			Buckets:        buckets,
+			Lifecycle: LifecycleConfig{
+				Enabled:  getEnvBool("STORAGE_LIFECYCLE_ENABLED", false),
+				DryRun:   getEnvBool("STORAGE_LIFECYCLE_DRY_RUN", true),
+				Interval: getEnvDuration("STORAGE_LIFECYCLE_INTERVAL", 24*time.Hour),
+				Rules:    rules,
+			},
+		},
+		Auth: AuthConfig{
+			Issuer:        getEnv("AUTH_ISSUER", "https://auth.bastet.cl"),
+			Audience:      getEnv("AUTH_AUDIENCE", "bastet-api"),
+			JWKSURL:       getEnv("AUTH_JWKS_URL", ""),
+			JWKSCacheTTL:  getEnvDuration("AUTH_JWKS_CACHE_TTL", 10*time.Minute),
+			PublicKeyFile: getEnv("AUTH_PUBLIC_KEY_FILE", ""),
+			ClockSkew:     getEnvDuration("AUTH_CLOCK_SKEW", 30*time.Second),
+
+			SigningKeyFile:  getEnv("AUTH_SIGNING_KEY_FILE", ""),
+			SigningKeyID:    getEnv("AUTH_SIGNING_KEY_ID", ""),
+			AccessTokenTTL:  getEnvDuration("AUTH_ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTTL: getEnvDuration("AUTH_REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		},
+		TLS: TLSConfig{
+			Enabled:           getEnvBool("TLS_ENABLED", false),
+			CertFile:          getEnv("TLS_CERT_FILE", ""),
+			KeyFile:           getEnv("TLS_KEY_FILE", ""),
+			CAFile:            getEnv("TLS_CA_FILE", ""),
+			AllowedClientSANs: getEnvList("TLS_ALLOWED_CLIENT_SANS"),
+			AllowedServerSANs: getEnvList("TLS_ALLOWED_SERVER_SANS"),
+			ReloadInterval:    getEnvDuration("TLS_RELOAD_INTERVAL", 30*time.Second),
+		},
+		Observability: ObservabilityConfig{
+			ServiceName:    getEnv("OTEL_SERVICE_NAME", "booking"),
+			ServiceVersion: getEnv("SERVICE_VERSION", "dev"),
+			Environment:    env,
+			Endpoint:       getEnv("OTEL_COLLECTOR_URL", ""),
+			Insecure:       getEnvBool("OTEL_COLLECTOR_INSECURE", false),
+			CAFile:         getEnv("OTEL_COLLECTOR_CA_FILE", ""),
+			Headers:        getEnvMap("OTEL_COLLECTOR_HEADERS"),
+			SampleRatio:    getEnvFloat("OTEL_TRACES_SAMPLE_RATIO", 1),
+			MetricInterval: getEnvDuration("OTEL_METRIC_INTERVAL", 30*time.Second),
+			Prometheus:     getEnvBool("PROMETHEUS_ENABLED", false),
 		},
 	}, nil
 }
@@ -121,6 +284,18 @@ func getEnvBool(key string, fallback bool) bool {
 	return b
 }
 
+func getEnvFloat(key string, fallback float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fallback
+	}
+	return f
+}
+
 func getEnvDuration(key string, fallback time.Duration) time.Duration {
 	v := os.Getenv(key)
 	if v == "" {
@@ -132,3 +307,41 @@ func getEnvDuration(key string, fallback time.Duration) time.Duration {
 	}
 	return d
 }
+
+// getEnvLifecycleRules parses a JSON array of rules. Unlike the other
+// helpers it fails on invalid input: silently falling back to the defaults
+// could delete objects the operator meant to keep.
+func getEnvLifecycleRules(key string, fallback []LifecycleRule) ([]LifecycleRule, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	var rules []LifecycleRule
+	if err := json.Unmarshal([]byte(v), &rules); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return rules, nil
+}
+
+// getEnvList splits a comma-separated variable, dropping empty entries.
+func getEnvList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// getEnvMap parses "k1=v1,k2=v2", the format OTel uses for headers.
+func getEnvMap(key string) map[string]string {
+	m := make(map[string]string)
+	for _, pair := range getEnvList(key) {
+		k, v, ok := strings.Cut(pair, "=")
+		if k = strings.TrimSpace(k); ok && k != "" {
+			m[k] = strings.TrimSpace(v)
+		}
+	}
+	return m
+}
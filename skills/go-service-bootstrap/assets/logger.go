@@ -1,22 +1,35 @@
-func setupLogger(env string) *slog.Logger {
+// setupLogger returns the level too: pass it to loglevel.NewAdminHandler
+// to change it at runtime. Call it after observability.Setup so records are
+// also exported over OTLP.
+func setupLogger(env, serviceName string) (*slog.Logger, *slog.LevelVar) {
     var handler slog.Handler
+    level := new(slog.LevelVar)
 
     switch env {
     case "production", "staging":
+        level.Set(slog.LevelInfo)
         handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-            Level: slog.LevelInfo,
+            Level: level,
         })
     case "development":
+        level.Set(slog.LevelDebug)
         handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-            Level:     slog.LevelDebug,
+            Level:     level,
             AddSource: true,
         })
     default: // local
+        level.Set(slog.LevelDebug)
         handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-            Level:     slog.LevelDebug,
+            Level:     level,
             AddSource: true,
         })
     }
 
-    return slog.New(handler)
+    handler = observability.NewLogHandler(handler, serviceName)
+
+    // Adds request_id, trace_id and span_id to every *Context call, redacts
+    // sensitive attributes and samples debug logs — see observability.ContextHandler
+    return slog.New(observability.NewContextHandler(handler, &observability.HandlerOptions{
+        DebugSampling: &observability.DebugSampling{Tick: time.Second, First: 10, Thereafter: 100},
+    })), level
 }
@@ -3,18 +3,27 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"api/booking/internal/booking/application"
 	bookingHandler "api/booking/internal/booking/infrastructure/handler"
 	bookingMessaging "api/booking/internal/booking/infrastructure/messaging"
 	bookingRepo "api/booking/internal/booking/infrastructure/repository"
+	"api/booking/internal/shared/auth/apikey"
+	"api/booking/internal/shared/auth/jwtauth"
 	"api/booking/internal/shared/config"
+	"api/booking/internal/shared/middleware"
+	"api/booking/internal/shared/mtls"
+	"api/booking/internal/shared/observability"
+	"api/booking/internal/shared/observability/loglevel"
 	"api/booking/internal/shared/server"
 	sharedStorage "api/booking/internal/shared/storage"
+	"github.com/gin-gonic/gin"
 )
 
 func main() {
@@ -25,13 +34,28 @@ func main() {
 		os.Exit(1)
 	}
 
-	// 2. Setup logger
-	setupLogger(cfg.Env)
-
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	// 3. Infrastructure — databases
+	// 2. Observability — traces, metrics and logs over OTLP, metrics on /metrics
+	shutdownTelemetry, err := observability.Setup(ctx, cfg.Observability)
+	if err != nil {
+		slog.Error("failed to set up observability", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		// ctx is already cancelled here; give the exporters time to flush.
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTelemetry(flushCtx); err != nil {
+			slog.Error("failed to flush telemetry", "error", err)
+		}
+	}()
+
+	// 3. Setup logger
+	logLevel := setupLogger(cfg.Env, cfg.Observability.ServiceName)
+
+	// 4. Infrastructure — databases
 	db, err := config.NewPostgresDB(cfg.Database)
 	if err != nil {
 		slog.Error("failed to connect to database", "error", err)
@@ -39,7 +63,7 @@ func main() {
 	}
 	defer db.Close()
 
-	// 4. Infrastructure — messaging
+	// 5. Infrastructure — messaging
 	publisher, err := newPublisher(cfg.Messaging)
 	if err != nil {
 		slog.Error("failed to create publisher", "error", err)
@@ -47,41 +71,89 @@ func main() {
 	}
 	defer publisher.Close()
 
-	// 5. Infrastructure — storage
+	// 6. Infrastructure — storage
 	store, err := sharedStorage.NewObjectStore(ctx, cfg.Storage)
 	if err != nil {
 		slog.Error("failed to create object store", "error", err)
 		os.Exit(1)
 	}
 
-	// 6. Repositories (adapters)
+	// 7. TLS — certificates are re-read when they change. The public API
+	// never asks for client certificates; mTLS (certs.ServerConfig) is for
+	// internal listeners such as gRPC.
+	var publicTLS, internalTLS *tls.Config
+	if cfg.TLS.Enabled {
+		certs, err := mtls.NewReloader(cfg.TLS)
+		if err != nil {
+			slog.Error("failed to load TLS certificates", "error", err)
+			os.Exit(1)
+		}
+		publicTLS, internalTLS = certs.PublicServerConfig(), certs.ServerConfig()
+	}
+
+	// 8. Auth — user JWTs or partner API keys on every /api/v1 route
+	tokenValidator, err := jwtauth.NewTokenValidator(cfg.Auth)
+	if err != nil {
+		slog.Error("failed to create token validator", "error", err)
+		os.Exit(1)
+	}
+	apiKeys := apikey.NewPostgresStore(db)
+
+	// 9. Repositories (adapters)
 	bookingRepository := bookingRepo.NewPostgresBookingRepository(db)
 
-	// 7. Application services (use cases)
+	// 10. Application services (use cases)
 	bookingService := application.NewBookingService(bookingRepository, publisher, nil)
 
-	// 8. HTTP handlers
+	// 11. HTTP handlers
 	bookingHTTP := bookingHandler.NewBookingHandler(bookingService)
 
-	// 9. Router
+	// 12. Router
 	router := server.NewRouter(cfg.Env)
-	v1 := router.Group("/api/v1")
+	router.Use(middleware.RequestID(), middleware.Tracing(cfg.Observability.ServiceName), middleware.Metrics())
+	v1 := router.Group("/api/v1", middleware.AuthRequiredWithAPIKeys(tokenValidator, apikey.NewValidator(apiKeys)))
 	bookingHTTP.RegisterRoutes(v1)
+	apikey.NewAdminHandler(apikey.NewManager(apiKeys)).RegisterRoutes(v1)
+	loglevel.NewAdminHandler(logLevel, loglevel.SystemClock{}).RegisterRoutes(v1)
+
+	// 13. Admin listener — /metrics on its own port, never on the public
+	// ingress. It's internal, so with TLS on it requires mTLS: Prometheus
+	// scrapes with a client certificate (see prometheus.yml). If it can't
+	// start, the whole service shuts down.
+	if cfg.Observability.Prometheus {
+		admin := gin.New()
+		admin.Use(gin.Recovery())
+		admin.GET("/metrics", gin.WrapH(observability.MetricsHandler()))
+		go func() {
+			if err := server.ListenAndServe(ctx, admin, cfg.Admin.Port, internalTLS); err != nil {
+				slog.Error("admin server error", "error", err)
+				cancel()
+			}
+		}()
+	}
 
-	// 10. Start server
-	slog.Info("starting server", "http_port", cfg.HTTP.Port, "env", cfg.Env)
-	if err := server.ListenAndServe(ctx, router, cfg.HTTP.Port); err != nil {
+	// 14. Start server
+	slog.Info("starting server", "http_port", cfg.HTTP.Port, "admin_port", cfg.Admin.Port, "env", cfg.Env, "tls", cfg.TLS.Enabled)
+	if err := server.ListenAndServe(ctx, router, cfg.HTTP.Port, publicTLS); err != nil {
 		slog.Error("server error", "error", err)
 		os.Exit(1)
 	}
 }
 
-func setupLogger(env string) {
+// setupLogger returns the level so /admin/log-level can change it.
+func setupLogger(env, serviceName string) *slog.LevelVar {
 	var handler slog.Handler
+	level := new(slog.LevelVar)
 	if env == "production" {
-		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})
+		level.Set(slog.LevelInfo)
+		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
 	} else {
-		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})
+		level.Set(slog.LevelDebug)
+		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level})
 	}
-	slog.SetDefault(slog.New(handler))
+	handler = observability.NewLogHandler(handler, serviceName)
+	slog.SetDefault(slog.New(observability.NewContextHandler(handler, &observability.HandlerOptions{
+		DebugSampling: &observability.DebugSampling{Tick: time.Second, First: 10, Thereafter: 100},
+	})))
+	return level
 }
@@ -0,0 +1,159 @@
+// internal/shared/mtls/mtls_test.go
+package mtls_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"api/booking/internal/shared/config"
+	"api/booking/internal/shared/mtls"
+	"api/booking/internal/shared/mtls/mtlstest"
+)
+
+func newReloader(t *testing.T, cfg config.TLSConfig) *mtls.Reloader {
+	t.Helper()
+	cfg.ReloadInterval = time.Nanosecond
+	r, err := mtls.NewReloader(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// serve starts an HTTPS server and returns its address.
+func serve(t *testing.T, cfg *tls.Config) string {
+	t.Helper()
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {})}
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+	return ln.Addr().String()
+}
+
+func get(addr, serverName string, cfg *tls.Config) error {
+	cfg.ServerName = serverName
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg, DisableKeepAlives: true}}
+	resp, err := client.Get("https://" + addr)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := mtlstest.NewCA(t)
+	serverCfg := ca.Config(t, "caregiver.test")
+	serverCfg.AllowedClientSANs = []string{"booking.test", "spiffe://bastet/booking"}
+	serverCfg.AllowedServerSANs = []string{"payments.test"} // Outbound only; must not admit payments
+	addr := serve(t, newReloader(t, serverCfg).ServerConfig())
+
+	tests := []struct {
+		name       string
+		client     config.TLSConfig
+		serverName string
+		wantErr    bool
+	}{
+		{"allowed DNS SAN", ca.Config(t, "booking.test"), "caregiver.test", false},
+		{"allowed URI SAN", ca.Config(t, "spiffe://bastet/booking"), "caregiver.test", false},
+		{"SAN not allowed", ca.Config(t, "payments.test"), "caregiver.test", true},
+		{"other CA", mtlstest.NewCA(t).Config(t, "booking.test"), "caregiver.test", true},
+		{"wrong server name", ca.Config(t, "booking.test"), "payments.test", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := get(addr, tt.serverName, newReloader(t, tt.client).ClientConfig())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("no client certificate", func(t *testing.T) {
+		roots := newReloader(t, ca.Config(t, "booking.test")).ClientConfig()
+		roots.GetClientCertificate = nil
+		if err := get(addr, "caregiver.test", roots); err == nil {
+			t.Fatal("expected handshake to fail")
+		}
+	})
+}
+
+func TestClientChecksAllowedServerSANs(t *testing.T) {
+	ca := mtlstest.NewCA(t)
+	caregiver := serve(t, newReloader(t, ca.Config(t, "caregiver.test")).ServerConfig())
+	payments := serve(t, newReloader(t, ca.Config(t, "payments.test")).ServerConfig())
+
+	clientCfg := ca.Config(t, "booking.test")
+	clientCfg.AllowedServerSANs = []string{"caregiver.test"}
+	clientCfg.AllowedClientSANs = []string{"payments.test"} // Inbound only
+	client := newReloader(t, clientCfg)
+
+	// The SAN list replaces the host-name check, so the dialed name doesn't matter.
+	if err := get(caregiver, "10.0.0.7", client.ClientConfig()); err != nil {
+		t.Errorf("allowed server: %v", err)
+	}
+	if err := get(payments, "payments.test", client.ClientConfig()); err == nil {
+		t.Error("server outside AllowedServerSANs should be rejected")
+	}
+}
+
+func TestPublicServerConfigNeedsNoClientCertificate(t *testing.T) {
+	ca := mtlstest.NewCA(t)
+	addr := serve(t, newReloader(t, ca.Config(t, "api.test")).PublicServerConfig())
+
+	browser := newReloader(t, ca.Config(t, "booking.test")).ClientConfig()
+	browser.GetClientCertificate = nil
+	if err := get(addr, "api.test", browser); err != nil {
+		t.Fatalf("client without a certificate: %v", err)
+	}
+}
+
+func TestReloadOnFileChange(t *testing.T) {
+	oldCA, newCA := mtlstest.NewCA(t), mtlstest.NewCA(t)
+	serverCfg := oldCA.Config(t, "caregiver.test")
+	clientCfg := oldCA.Config(t, "booking.test")
+	addr := serve(t, newReloader(t, serverCfg).ServerConfig())
+	client := newReloader(t, clientCfg)
+
+	if err := get(addr, "caregiver.test", client.ClientConfig()); err != nil {
+		t.Fatalf("before rotation: %v", err)
+	}
+
+	// Rotate both sides to the new CA, the way a secret update would.
+	for _, side := range []struct {
+		cfg config.TLSConfig
+		san string
+	}{{serverCfg, "caregiver.test"}, {clientCfg, "booking.test"}} {
+		cert, key := newCA.Issue(t, side.san)
+		mtlstest.WriteFile(t, side.cfg.CertFile, cert)
+		mtlstest.WriteFile(t, side.cfg.KeyFile, key)
+		mtlstest.WriteFile(t, side.cfg.CAFile, newCA.PEM)
+	}
+
+	if err := get(addr, "caregiver.test", client.ClientConfig()); err != nil {
+		t.Fatalf("after rotation: %v", err)
+	}
+	stale := newReloader(t, oldCA.Config(t, "booking.test"))
+	if err := get(addr, "caregiver.test", stale.ClientConfig()); err == nil {
+		t.Fatal("client on the old CA should be rejected after rotation")
+	}
+}
+
+func TestKeepsCurrentCertificateOnBrokenReload(t *testing.T) {
+	ca := mtlstest.NewCA(t)
+	serverCfg := ca.Config(t, "caregiver.test")
+	addr := serve(t, newReloader(t, serverCfg).ServerConfig())
+
+	// A new cert without its key yet: the pair doesn't match.
+	cert, _ := ca.Issue(t, "caregiver.test")
+	mtlstest.WriteFile(t, serverCfg.CertFile, cert)
+
+	client := newReloader(t, ca.Config(t, "booking.test")).ClientConfig()
+	if err := get(addr, "caregiver.test", client); err != nil {
+		t.Fatalf("server should keep serving the previous pair: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+// internal/shared/mtls/mtlstest/mtlstest.go
+package mtlstest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"api/booking/internal/shared/config"
+)
+
+// CA is an in-memory certificate authority for tests. Nothing touches disk
+// until Config writes a service's files to a test temp dir.
+type CA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	PEM  []byte
+}
+
+func NewCA(t testing.TB) *CA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial(t),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &CA{cert: cert, key: key, PEM: encode("CERTIFICATE", der)}
+}
+
+// Issue returns a certificate and PKCS#8 key, valid for both server and
+// client auth. SANs containing "://" become URIs, IPs become IP SANs and
+// the rest DNS names; the first one is also the common name.
+func (ca *CA) Issue(t testing.TB, sans ...string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial(t),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	for _, san := range sans {
+		switch {
+		case strings.Contains(san, "://"):
+			u, err := url.Parse(san)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tmpl.URIs = append(tmpl.URIs, u)
+		case net.ParseIP(san) != nil:
+			tmpl.IPAddresses = append(tmpl.IPAddresses, net.ParseIP(san))
+		default:
+			tmpl.DNSNames = append(tmpl.DNSNames, san)
+		}
+	}
+	if len(sans) > 0 {
+		tmpl.Subject.CommonName = sans[0]
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encode("CERTIFICATE", der), encode("PRIVATE KEY", pkcs8)
+}
+
+// Config issues a certificate for sans and writes it, its key and the CA to
+// a temp dir, returning a config.TLSConfig pointing at them. Rewrite the
+// files with WriteFile to test reloads.
+func (ca *CA) Config(t testing.TB, sans ...string) config.TLSConfig {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.TLSConfig{
+		Enabled:  true,
+		CertFile: filepath.Join(dir, "cert.pem"),
+		KeyFile:  filepath.Join(dir, "key.pem"),
+		CAFile:   filepath.Join(dir, "ca.pem"),
+	}
+	certPEM, keyPEM := ca.Issue(t, sans...)
+	WriteFile(t, cfg.CertFile, certPEM)
+	WriteFile(t, cfg.KeyFile, keyPEM)
+	WriteFile(t, cfg.CAFile, ca.PEM)
+	return cfg
+}
+
+// WriteFile replaces path and moves its modification time forward, so a
+// Reloader sees the change even within the file system's time resolution.
+func WriteFile(t testing.TB, path string, data []byte) {
+	t.Helper()
+	mod := time.Now()
+	if info, err := os.Stat(path); err == nil && !info.ModTime().Before(mod) {
+		mod = info.ModTime().Add(time.Second)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func serial(t testing.TB) *big.Int {
+	t.Helper()
+	n, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func encode(typ string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
@@ -3,6 +3,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -11,10 +12,13 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func ListenAndServe(ctx context.Context, router *gin.Engine, port int) error {
+// ListenAndServe serves HTTPS when tlsConfig is set (mtls.Reloader's
+// PublicServerConfig for the public API), plain HTTP otherwise.
+func ListenAndServe(ctx context.Context, router *gin.Engine, port int, tlsConfig *tls.Config) error {
 	srv := &http.Server{
-		Addr:    fmt.Sprintf(":%d", port),
-		Handler: router,
+		Addr:      fmt.Sprintf(":%d", port),
+		Handler:   router,
+		TLSConfig: tlsConfig,
 	}
 
 	go func() {
@@ -27,7 +31,13 @@ func ListenAndServe(ctx context.Context, router *gin.Engine, port int) error {
 		}
 	}()
 
-	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+	var err error
+	if tlsConfig != nil {
+		err = srv.ListenAndServeTLS("", "") // Certificates come from TLSConfig
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != http.ErrServerClosed {
 		return err
 	}
 	return nil